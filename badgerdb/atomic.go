@@ -0,0 +1,86 @@
+package badgerdb
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/rawbytedev/zerokv"
+)
+
+// CompareAndSwap replaces the value of key with new if it equals old, inside a transaction.
+func (b *badgerDB) CompareAndSwap(ctx context.Context, key []byte, old []byte, new []byte) (bool, error) {
+	return b.modify(ctx, key, func(cur []byte, found bool) ([]byte, zerokv.Ops, error) {
+		if found != (old != nil) || !bytes.Equal(cur, old) {
+			return nil, zerokv.GetOp, nil
+		}
+		return new, zerokv.PutOp, nil
+	})
+}
+
+// PutIfAbsent stores value only if key does not exist, inside a transaction.
+func (b *badgerDB) PutIfAbsent(ctx context.Context, key []byte, value []byte) (bool, error) {
+	return b.modify(ctx, key, func(cur []byte, found bool) ([]byte, zerokv.Ops, error) {
+		if found {
+			return nil, zerokv.GetOp, nil
+		}
+		return value, zerokv.PutOp, nil
+	})
+}
+
+// DeleteIfEqual deletes key if its value equals old, inside a transaction.
+func (b *badgerDB) DeleteIfEqual(ctx context.Context, key []byte, old []byte) (bool, error) {
+	return b.modify(ctx, key, func(cur []byte, found bool) ([]byte, zerokv.Ops, error) {
+		if !found || !bytes.Equal(cur, old) {
+			return nil, zerokv.GetOp, nil
+		}
+		return nil, zerokv.DeleteOp, nil
+	})
+}
+
+// UpdateFunc replaces the value of key with the result of fn, inside a transaction.
+func (b *badgerDB) UpdateFunc(ctx context.Context, key []byte, fn func(old []byte) ([]byte, error)) error {
+	_, err := b.modify(ctx, key, func(cur []byte, found bool) ([]byte, zerokv.Ops, error) {
+		next, err := fn(cur)
+		switch {
+		case err != nil:
+			return nil, zerokv.GetOp, err
+		case next != nil:
+			return next, zerokv.PutOp, nil
+		case found:
+			return nil, zerokv.DeleteOp, nil
+		}
+		return nil, zerokv.GetOp, nil
+	})
+	return err
+}
+
+// modify reads key and applies the operation chosen by fn in one transaction, retried on conflicts.
+// zerokv.GetOp leaves the key untouched, modify reports whether a write happened.
+func (b *badgerDB) modify(ctx context.Context, key []byte, fn func(cur []byte, found bool) ([]byte, zerokv.Ops, error)) (bool, error) {
+	if err := checkKey(key); err != nil {
+		return false, err
+	}
+	var written bool
+	err := b.Update(ctx, func(tx zerokv.Txn) error {
+		cur, err := tx.Get(key)
+		if err != nil && !zerokv.IsNotFound(err) {
+			return err
+		}
+		next, op, err := fn(cur, err == nil)
+		if err != nil {
+			return err
+		}
+		written = op != zerokv.GetOp
+		switch op {
+		case zerokv.PutOp:
+			return tx.Put(key, next)
+		case zerokv.DeleteOp:
+			return tx.Delete(key)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return written, nil
+}
@@ -1,23 +1,47 @@
 package badgerdb
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"sync/atomic"
+	"time"
 
 	"github.com/rawbytedev/zerokv"
 
 	"github.com/dgraph-io/badger/v4"
 )
 
+// maxKeySize is badger's hard limit on key length.
+const maxKeySize = 65000
+
 type badgerDB struct {
-	db *badger.DB
+	db     *badger.DB
+	retry  zerokv.RetryPolicy
+	clock  func() time.Time
+	closed atomic.Bool
 }
 type badgerBatch struct {
+	db    *badgerDB
 	batch *badger.WriteBatch
+	// pending overlays the staged writes for Get, Scan and DeleteRange
+	pending map[string]pendingWrite
+	// ranges are the DeleteRange calls whose stored keys are deleted at Commit
+	ranges []keyRange
+	// ops records the staged operations for Encode
+	ops  []zerokv.Operations
+	size int
+	// done is set once the batch is flushed or cancelled, Reset clears it
+	done bool
 }
 
 type badgerIterator struct {
 	Iterator *badger.Iterator
+	db       *badgerDB
+	txn      *badger.Txn
+	ctx      context.Context
+	opts     zerokv.ScanOptions
+	count    int
 	started  bool
 	valid    bool
 	err      []error
@@ -35,53 +59,227 @@ func NewBadgerDB(cfg Config) (zerokv.Core, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &badgerDB{db: db}, nil
+	retry := zerokv.DefaultRetryPolicy
+	if cfg.Retry != nil {
+		retry = *cfg.Retry
+	}
+	clock := time.Now
+	if cfg.Clock != nil {
+		clock = cfg.Clock
+	}
+	return &badgerDB{db: db, retry: retry, clock: clock}, nil
 }
 
 // --- Basic CRUD operations ---
 
 // Put inserts or updates a key-value pair in the database.
-func (b *badgerDB) Put(ctx context.Context, key, value []byte) error {
-	if err := ctx.Err(); err != nil {
+func (b *badgerDB) Put(ctx context.Context, key, value []byte, opts ...zerokv.WriteOptions) error {
+	if err := b.check(ctx); err != nil {
 		return err
 	}
-	return b.db.Update(func(txn *badger.Txn) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	err := b.db.Update(func(txn *badger.Txn) error {
 		return txn.Set(key, value)
 	})
+	if err != nil {
+		return convertError(err)
+	}
+	return b.syncWrite(opts)
 }
 
 // Get retrieves the value for a given key. Returns an error if not found.
 func (b *badgerDB) Get(ctx context.Context, key []byte) ([]byte, error) {
-	if err := ctx.Err(); err != nil {
+	if err := b.check(ctx); err != nil {
 		return nil, err
 	}
 	var data []byte
 	err := b.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(key)
+		var err error
+		data, err = b.getValue(txn, key)
+		return err
+	})
+	return data, err
+}
+
+// getItem looks key up within txn, expired items are reported as zerokv.ErrNotFound.
+func (b *badgerDB) getItem(txn *badger.Txn, key []byte) (*badger.Item, error) {
+	item, err := txn.Get(key)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if b.expired(item) {
+		return nil, zerokv.ErrNotFound
+	}
+	return item, nil
+}
+
+// getValue reads a caller-owned copy of the value for key within txn.
+func (b *badgerDB) getValue(txn *badger.Txn, key []byte) ([]byte, error) {
+	item, err := b.getItem(txn, key)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	err = item.Value(func(val []byte) error {
+		val, err := payload(item, val)
+		data = copyBytes(val)
+		return err
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return data, nil
+}
+
+// GetFunc calls fn with the value for key, val is only valid inside fn.
+func (b *badgerDB) GetFunc(ctx context.Context, key []byte, fn func(val []byte) error) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	return convertError(b.db.View(func(txn *badger.Txn) error {
+		item, err := b.getItem(txn, key)
 		if err != nil {
 			return err
 		}
 		return item.Value(func(val []byte) error {
-			data = make([]byte, len(val))
-			copy(data, val)
-			return nil
+			val, err := payload(item, val)
+			if err != nil {
+				return err
+			}
+			return fn(val)
 		})
+	}))
+}
+
+// GetMany retrieves every key within a single read transaction.
+func (b *badgerDB) GetMany(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+	values := make([][]byte, len(keys))
+	errs := make([]error, len(keys))
+	if err := b.check(ctx); err != nil {
+		return values, fillErrors(errs, err)
+	}
+	err := b.db.View(func(txn *badger.Txn) error {
+		for i, key := range keys {
+			values[i], errs[i] = b.getValue(txn, key)
+		}
+		return nil
 	})
-	return data, err
+	if err != nil {
+		return values, fillErrors(errs, convertError(err))
+	}
+	return values, errs
+}
+
+// PutMany writes all pairs through one batch.
+func (b *badgerDB) PutMany(ctx context.Context, keys [][]byte, values [][]byte) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	if len(keys) != len(values) {
+		return zerokv.ErrLengthMismatch
+	}
+	batch := b.Batch()
+	defer batch.Discard()
+	for i := range keys {
+		if err := batch.Put(keys[i], values[i]); err != nil {
+			return err
+		}
+	}
+	return batch.Commit(ctx)
+}
+
+// fillErrors reports err for every key of a multi-key read that failed as a whole.
+func fillErrors(errs []error, err error) []error {
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Has reports whether key exists, only the key metadata is read.
+func (b *badgerDB) Has(ctx context.Context, key []byte) (bool, error) {
+	found, err := b.HasMany(ctx, [][]byte{key})
+	if err != nil {
+		return false, err
+	}
+	return found[0], nil
+}
+
+// HasMany reports whether each key exists within a single read transaction.
+func (b *badgerDB) HasMany(ctx context.Context, keys [][]byte) ([]bool, error) {
+	if err := b.check(ctx); err != nil {
+		return nil, err
+	}
+	found := make([]bool, len(keys))
+	err := b.db.View(func(txn *badger.Txn) error {
+		for i, key := range keys {
+			// txn.Get only loads the item header, the value stays in the value log
+			_, err := b.getItem(txn, key)
+			switch {
+			case err == nil:
+				found[i] = true
+			case !zerokv.IsNotFound(err):
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return found, nil
 }
 
 // Delete removes a key-value pair from the database.
-func (b *badgerDB) Delete(ctx context.Context, key []byte) error {
-	if err := ctx.Err(); err != nil {
+func (b *badgerDB) Delete(ctx context.Context, key []byte, opts ...zerokv.WriteOptions) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	if err := checkKey(key); err != nil {
 		return err
 	}
-	return b.db.Update(func(txn *badger.Txn) error {
+	err := b.db.Update(func(txn *badger.Txn) error {
 		return txn.Delete(key)
 	})
+	if err != nil {
+		return convertError(err)
+	}
+	return b.syncWrite(opts)
+}
+
+// Sync syncs the WAL and value log, making every write acknowledged so far durable.
+func (b *badgerDB) Sync(ctx context.Context) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	if b.db.Opts().InMemory {
+		return nil
+	}
+	return convertError(b.db.Sync())
+}
+
+// syncWrite syncs a committed write when opts ask for it.
+/*
+	Badger commits sync on their own with SyncWrites, which cannot be turned off per
+	write, so writes on such a store are synced whatever opts say.
+*/
+func (b *badgerDB) syncWrite(opts []zerokv.WriteOptions) error {
+	if !zerokv.WriteOptionsOf(opts).Sync {
+		return nil
+	}
+	if o := b.db.Opts(); o.SyncWrites || o.InMemory {
+		return nil
+	}
+	return convertError(b.db.Sync())
 }
 
 // Close closes the BadgerDB instance and releases all resources.
 func (b *badgerDB) Close() error {
+	if !b.closed.CompareAndSwap(false, true) {
+		return zerokv.ErrClosed
+	}
 	var errs []error
 	if b.db != nil {
 		if err := b.db.Close(); err != nil {
@@ -94,49 +292,386 @@ func (b *badgerDB) Close() error {
 	return errors.Join(errs...)
 }
 
+// check reports context cancellation or use of a closed database.
+func (b *badgerDB) check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if b.closed.Load() {
+		return zerokv.ErrClosed
+	}
+	return nil
+}
+
+// checkKey validates a key before it reaches badger.
+func checkKey(key []byte) error {
+	if len(key) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	if len(key) > maxKeySize {
+		return zerokv.ErrKeyTooLarge
+	}
+	return nil
+}
+
+// copyBytes detaches a slice from memory owned by badger or the caller.
+func copyBytes(b []byte) []byte {
+	data := make([]byte, len(b))
+	copy(data, b)
+	return data
+}
+
+// convertError translates badger errors into zerokv sentinels.
+func convertError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, badger.ErrKeyNotFound):
+		return zerokv.ErrNotFound
+	case errors.Is(err, badger.ErrEmptyKey):
+		return zerokv.ErrEmptyKey
+	case errors.Is(err, badger.ErrDBClosed):
+		return zerokv.Wrap(zerokv.ErrClosed, err)
+	case errors.Is(err, badger.ErrBlockedWrites):
+		// badger also blocks writes while dropping keys, a closed store is caught by check
+		return zerokv.Wrap(zerokv.ErrWritesBlocked, err)
+	case errors.Is(err, badger.ErrDiscardedTxn):
+		return zerokv.Wrap(zerokv.ErrBatchCommitted, err)
+	case errors.Is(err, badger.ErrReadOnlyTxn):
+		return zerokv.ErrReadOnly
+	case errors.Is(err, badger.ErrConflict):
+		return zerokv.Wrap(zerokv.ErrConflict, err)
+	case errors.Is(err, badger.ErrTxnTooBig):
+		return zerokv.Wrap(zerokv.ErrTxnTooBig, err)
+	}
+	return err
+}
+
 // -- Batch operations
 
 // Batch creates a new batch operation for the BadgerDB instance.
 func (b *badgerDB) Batch() zerokv.Batch {
-	return &badgerBatch{batch: b.db.NewWriteBatch()}
+	return &badgerBatch{db: b, batch: b.db.NewWriteBatch(), pending: make(map[string]pendingWrite)}
+}
+
+// ApplyEncodedBatch commits the operations of an encoded batch through one write batch.
+func (b *badgerDB) ApplyEncodedBatch(ctx context.Context, data []byte) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	ops, err := zerokv.DecodeOperations(data)
+	if err != nil {
+		return err
+	}
+	batch := b.Batch()
+	defer batch.Discard()
+	if err := zerokv.ApplyOperations(batch, ops, b.clock()); err != nil {
+		return err
+	}
+	return batch.Commit(ctx)
 }
 
 // Put inserts or updates a key-value pair in the batch.
 func (b *badgerBatch) Put(key, value []byte) error {
-	return b.batch.Set(key, value)
+	return b.set(key, value, time.Time{})
+}
+
+// set stages key and value expiring at expiresAt, the zero time never expires.
+func (b *badgerBatch) set(key, value []byte, expiresAt time.Time) error {
+	if b.done {
+		return zerokv.ErrBatchCommitted
+	}
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	// badger keeps references until Flush, copy so callers can reuse their buffers
+	key, value = copyBytes(key), copyBytes(value)
+	if err := b.batch.SetEntry(b.db.newEntry(key, value, expiresAt)); err != nil {
+		return convertError(err)
+	}
+	b.pending[string(key)] = pendingWrite{value: value, expiresAt: expiresAt}
+	b.record(zerokv.Operations{Key: key, Value: value, ExpiresAt: expiresAt, Type: zerokv.PutOp})
+	return nil
 }
 
 // Delete removes a key-value pair from the batch.
 func (b *badgerBatch) Delete(key []byte) error {
-	return b.batch.Delete(key)
+	if b.done {
+		return zerokv.ErrBatchCommitted
+	}
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	key = copyBytes(key)
+	if err := b.stageDelete(key); err != nil {
+		return err
+	}
+	b.record(zerokv.Operations{Key: key, Type: zerokv.DeleteOp})
+	return nil
+}
+
+// record keeps op for Encode and accounts for its size.
+func (b *badgerBatch) record(op zerokv.Operations) {
+	b.ops = append(b.ops, op)
+	b.size += len(op.Key) + len(op.Value) + len(op.End)
 }
 
 // Commits commits the batch operations to the database.
-func (b *badgerBatch) Commit(ctx context.Context) error {
+func (b *badgerBatch) Commit(ctx context.Context, opts ...zerokv.WriteOptions) error {
+	if b.done {
+		return zerokv.ErrBatchCommitted
+	}
+	// badger commits the parts of an oversized batch while staging, ctx only holds back the rest
+	// and Flush cannot be interrupted
 	if err := ctx.Err(); err != nil {
 		return err
 	}
-	return b.batch.Flush()
+	b.done = true
+	if err := b.deleteRanges(); err != nil {
+		b.batch.Cancel()
+		return err
+	}
+	if err := b.batch.Flush(); err != nil {
+		return convertError(err)
+	}
+	return b.db.syncWrite(opts)
+}
+
+// Discard cancels the write batch, operations already flushed by badger are kept.
+func (b *badgerBatch) Discard() {
+	if !b.done {
+		b.done = true
+		b.batch.Cancel()
+	}
+}
+
+// Reset drops the staged operations so the batch can be reused, even after Commit.
+func (b *badgerBatch) Reset() {
+	b.Discard()
+	b.batch = b.db.db.NewWriteBatch()
+	clear(b.pending)
+	b.ranges = nil
+	b.ops = nil
+	b.size, b.done = 0, false
+}
+
+// Encode returns the staged operations in the zerokv encoded batch format.
+func (b *badgerBatch) Encode() []byte {
+	return zerokv.EncodeOperations(b.ops)
+}
+
+// Savepoint marks the operations staged so far.
+func (b *badgerBatch) Savepoint() int {
+	return len(b.ops)
+}
+
+// RollbackTo rebuilds the write batch from the operations staged before sp.
+/*
+	Like Discard, it cannot undo operations badger already flushed from an oversized batch.
+*/
+func (b *badgerBatch) RollbackTo(sp int) error {
+	if b.done {
+		return zerokv.ErrBatchCommitted
+	}
+	if sp < 0 || sp > len(b.ops) {
+		return zerokv.ErrInvalidSavepoint
+	}
+	if sp == len(b.ops) {
+		return nil
+	}
+	// a write batch cannot be truncated, the kept operations are staged again on a fresh one
+	kept := b.ops[:sp]
+	b.Reset()
+	for _, op := range kept {
+		var err error
+		switch op.Type {
+		case zerokv.PutOp:
+			// the recorded expiry is kept as is, not turned back into a TTL
+			err = b.set(op.Key, op.Value, op.ExpiresAt)
+		case zerokv.DeleteOp:
+			err = b.Delete(op.Key)
+		case zerokv.DeleteRangeOp:
+			err = b.DeleteRange(op.Key, op.End)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of operations staged.
+func (b *badgerBatch) Len() int {
+	return len(b.ops)
+}
+
+// ByteSize returns the total size of the keys and values staged.
+func (b *badgerBatch) ByteSize() int {
+	return b.size
 }
 
 // -- Iterator operations
 
 func (b *badgerDB) Scan(prefix []byte) zerokv.Iterator {
-	txn := b.db.NewTransaction(false)
-	it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, PrefetchValues: true})
-	return &badgerIterator{Iterator: it}
+	return b.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext iterates over keys starting with prefix, the iterator stops once ctx is done.
+func (b *badgerDB) ScanContext(ctx context.Context, prefix []byte) zerokv.Iterator {
+	return b.ScanRange(ctx, zerokv.ScanOptions{Prefix: prefix})
 }
+
+// ScanKeys iterates over keys starting with prefix, values are not prefetched from the value log.
+func (b *badgerDB) ScanKeys(ctx context.Context, prefix []byte) zerokv.Iterator {
+	return b.ScanRange(ctx, zerokv.ScanOptions{Prefix: prefix, KeysOnly: true})
+}
+
+// ForEach calls fn for every key under prefix, key and val are only valid inside fn.
+func (b *badgerDB) ForEach(ctx context.Context, prefix []byte, fn func(key, val []byte) error) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	return convertError(b.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, PrefetchValues: true})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			item := it.Item()
+			if b.expired(item) {
+				continue
+			}
+			if err := item.Value(func(val []byte) error {
+				val, err := payload(item, val)
+				if err != nil {
+					return err
+				}
+				return fn(item.Key(), val)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// ScanRange iterates over keys in [opts.Start, opts.End).
+func (b *badgerDB) ScanRange(ctx context.Context, opts zerokv.ScanOptions) zerokv.Iterator {
+	if err := b.check(ctx); err != nil {
+		return zerokv.NewErrIterator(err)
+	}
+	return b.newIterator(ctx, b.db.NewTransaction(false), true, opts)
+}
+
+// newIterator opens an iterator over txn, the txn is discarded on Release when owned.
+func (b *badgerDB) newIterator(ctx context.Context, txn *badger.Txn, owned bool, opts zerokv.ScanOptions) *badgerIterator {
+	opts.Start, opts.End = opts.Bounds()
+	iopts := badger.IteratorOptions{
+		PrefetchValues: !opts.KeysOnly,
+		Reverse:        opts.Reverse,
+	}
+	// badger rewinds a reverse prefix iterator onto the prefix itself,
+	// so the prefix is only handed to badger for forward scans
+	if !opts.Reverse {
+		iopts.Prefix = opts.Prefix
+	}
+	it := &badgerIterator{Iterator: txn.NewIterator(iopts), db: b, ctx: ctx, opts: opts}
+	if owned {
+		it.txn = txn
+	}
+	return it
+}
+
 func (it *badgerIterator) Next() bool {
 	if !it.started {
-		it.Iterator.Rewind()
-		it.started = true
+		return it.First()
+	}
+	if !it.ready() {
+		return false
+	}
+	it.Iterator.Next()
+	return it.settle()
+}
+
+// First moves back to the first entry of the scan.
+func (it *badgerIterator) First() bool {
+	it.started, it.count = true, 0
+	if !it.ready() {
+		return false
+	}
+	it.rewind()
+	return it.settle()
+}
+
+// Seek moves to the first key >= key, or the last key <= key for reverse scans.
+func (it *badgerIterator) Seek(key []byte) bool {
+	it.started, it.count = true, 0
+	if !it.ready() {
+		return false
+	}
+	// keys outside the scan bounds are clamped to the start of the scan
+	if it.opts.Reverse && (it.opts.End == nil || bytes.Compare(key, it.opts.End) < 0) ||
+		!it.opts.Reverse && (it.opts.Start == nil || bytes.Compare(key, it.opts.Start) > 0) {
+		it.Iterator.Seek(key)
 	} else {
+		it.rewind()
+	}
+	return it.settle()
+}
+
+// ready checks the context and the scan limit before moving the iterator.
+func (it *badgerIterator) ready() bool {
+	if err := it.ctx.Err(); err != nil {
+		it.err = append(it.err, err)
+		it.valid = false
+		return false
+	}
+	if it.opts.Limit > 0 && it.count >= it.opts.Limit {
+		it.valid = false
+		return false
+	}
+	return true
+}
+
+// settle records whether the iterator landed on an entry within the scan.
+func (it *badgerIterator) settle() bool {
+	// expired entries are skipped in the direction of the scan
+	for it.Iterator.Valid() && it.db.expired(it.Iterator.Item()) {
 		it.Iterator.Next()
 	}
-	it.valid = it.Iterator.Valid()
+	it.valid = it.Iterator.Valid() && it.inRange()
+	if it.valid {
+		it.count++
+	}
 	return it.valid
 }
 
+// rewind positions the iterator on the first key within the scan bounds.
+func (it *badgerIterator) rewind() {
+	switch {
+	case !it.opts.Reverse && it.opts.Start != nil:
+		it.Iterator.Seek(it.opts.Start)
+	case it.opts.Reverse && it.opts.End != nil:
+		// reverse Seek lands on the largest key <= End, End itself is excluded
+		it.Iterator.Seek(it.opts.End)
+		if it.Iterator.Valid() && bytes.Equal(it.Iterator.Item().Key(), it.opts.End) {
+			it.Iterator.Next()
+		}
+	default:
+		it.Iterator.Rewind()
+	}
+}
+
+// inRange checks the bound badger cannot enforce on its own.
+func (it *badgerIterator) inRange() bool {
+	key := it.Iterator.Item().Key()
+	if it.opts.Reverse {
+		return it.opts.Start == nil || bytes.Compare(key, it.opts.Start) >= 0
+	}
+	return it.opts.End == nil || bytes.Compare(key, it.opts.End) < 0
+}
+
 func (it *badgerIterator) Key() []byte {
 	if !it.valid {
 		return nil
@@ -144,10 +679,14 @@ func (it *badgerIterator) Key() []byte {
 	return it.Iterator.Item().KeyCopy(nil) // safer, doesn't make changes to key
 }
 func (it *badgerIterator) Value() []byte {
-	if !it.valid {
+	if !it.valid || it.opts.KeysOnly {
 		return nil
 	}
-	data, err := it.Iterator.Item().ValueCopy(nil)
+	item := it.Iterator.Item()
+	data, err := item.ValueCopy(nil)
+	if err == nil {
+		data, err = payload(item, data)
+	}
 	if err != nil {
 		it.err = append(it.err, err)
 		return []byte{}
@@ -157,7 +696,11 @@ func (it *badgerIterator) Value() []byte {
 
 // Release Must be called to avoid memory leaks
 func (it *badgerIterator) Release() {
+	it.valid = false
 	it.Iterator.Close()
+	if it.txn != nil {
+		it.txn.Discard()
+	}
 }
 
 func (it *badgerIterator) Error() error {
@@ -170,34 +713,14 @@ func (it *badgerIterator) Error() error {
 //  --- specials methods to use with an instance of badgerdb for some other operations
 
 func NewIterator(b *badgerDB) zerokv.Iterator {
-	it := &badger.Iterator{}
-	b.db.View(func(txn *badger.Txn) error {
-		it = txn.NewIterator(badger.IteratorOptions{})
-		return nil
-	})
-	return &badgerIterator{Iterator: it}
+	return b.ScanRange(context.Background(), zerokv.ScanOptions{})
 }
 func NewReverseIterator(b *badgerDB) zerokv.Iterator {
-	it := &badger.Iterator{}
-	b.db.View(func(txn *badger.Txn) error {
-		it = txn.NewIterator(badger.IteratorOptions{Reverse: true})
-		return nil
-	})
-	return &badgerIterator{Iterator: it}
+	return b.ScanRange(context.Background(), zerokv.ScanOptions{Reverse: true})
 }
 func NewPrefixIterator(b *badgerDB, prefix []byte) zerokv.Iterator {
-	it := &badger.Iterator{}
-	b.db.View(func(txn *badger.Txn) error {
-		it = txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
-		return nil
-	})
-	return &badgerIterator{Iterator: it}
+	return b.ScanRange(context.Background(), zerokv.ScanOptions{Prefix: prefix})
 }
 func NewReversePrefixIterator(b *badgerDB, prefix []byte) zerokv.Iterator {
-	it := &badger.Iterator{}
-	b.db.View(func(txn *badger.Txn) error {
-		it = txn.NewIterator(badger.IteratorOptions{Prefix: prefix, Reverse: true})
-		return nil
-	})
-	return &badgerIterator{Iterator: it}
+	return b.ScanRange(context.Background(), zerokv.ScanOptions{Prefix: prefix, Reverse: true})
 }
@@ -0,0 +1,127 @@
+package badgerdb
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/rawbytedev/zerokv"
+)
+
+/*
+Badger has no range tombstones. A batch remembers its ranges: keys put earlier in
+the batch are deleted right away, batch reads hide stored keys in range and Commit
+streams a delete for every stored key in range into the write batch, skipping keys
+the batch writes itself. The write batch commits in parts when it grows too large,
+so a large range is not deleted atomically. DropPrefix is not used: it blocks every
+write to the store while it runs, so unrelated writers would fail.
+*/
+
+// keyRange is a range staged by DeleteRange, a nil end is open.
+type keyRange struct {
+	start, end []byte
+}
+
+// DeleteRange deletes every key in [start, end) through a single write batch.
+func (b *badgerDB) DeleteRange(ctx context.Context, start, end []byte) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	batch := b.Batch()
+	defer batch.Discard()
+	if err := batch.DeleteRange(start, end); err != nil {
+		return err
+	}
+	return batch.Commit(ctx)
+}
+
+// DeletePrefix deletes every key starting with prefix through a single write batch.
+func (b *badgerDB) DeletePrefix(ctx context.Context, prefix []byte) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	if len(prefix) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	return b.DeleteRange(ctx, prefix, zerokv.PrefixUpperBound(prefix))
+}
+
+// DeleteRange deletes the keys in [start, end) put earlier in the batch and stored keys at Commit.
+func (b *badgerBatch) DeleteRange(start, end []byte) error {
+	if b.done {
+		return zerokv.ErrBatchCommitted
+	}
+	op := zerokv.Operations{Key: copyBytes(start), Type: zerokv.DeleteRangeOp}
+	if end != nil {
+		op.End = copyBytes(end)
+	}
+	b.record(op)
+	if end != nil && bytes.Compare(start, end) >= 0 {
+		return nil
+	}
+	for key, w := range b.pending {
+		if !w.deleted && inRange([]byte(key), start, end) {
+			if err := b.stageDelete([]byte(key)); err != nil {
+				return err
+			}
+		}
+	}
+	b.ranges = append(b.ranges, keyRange{start: op.Key, end: op.End})
+	return nil
+}
+
+// deleteRanges stages a delete for every stored key in the staged ranges that the batch does not write itself.
+func (b *badgerBatch) deleteRanges() error {
+	for _, r := range b.ranges {
+		opts := zerokv.ScanOptions{Start: r.start, End: r.end, KeysOnly: true}
+		it := b.db.newIterator(context.Background(), b.db.db.NewTransaction(false), true, opts)
+		for it.Next() {
+			key := it.Key()
+			// a key staged after the range wins, one staged before it already has its delete
+			if _, ok := b.pending[string(key)]; ok {
+				continue
+			}
+			if err := b.batch.Delete(key); err != nil {
+				it.Release()
+				return convertError(err)
+			}
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return convertError(err)
+		}
+	}
+	return nil
+}
+
+// inRanges reports whether key falls in a range staged by DeleteRange.
+func inRanges(ranges []keyRange, key []byte) bool {
+	for _, r := range ranges {
+		if inRange(key, r.start, r.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// stageDelete stages a delete of key, badger holds on to key until Flush.
+func (b *badgerBatch) stageDelete(key []byte) error {
+	if err := b.batch.Delete(key); err != nil {
+		return convertError(err)
+	}
+	b.pending[string(key)] = pendingWrite{deleted: true}
+	return nil
+}
+
+// DeletePrefix stages a delete for every key starting with prefix.
+func (b *badgerBatch) DeletePrefix(prefix []byte) error {
+	if len(prefix) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	return b.DeleteRange(prefix, zerokv.PrefixUpperBound(prefix))
+}
+
+// inRange reports whether key falls within [start, end), a nil end is open.
+func inRange(key, start, end []byte) bool {
+	return bytes.Compare(key, start) >= 0 && (end == nil || bytes.Compare(key, end) < 0)
+}
@@ -1,13 +1,22 @@
 package badgerdb
 
-import "github.com/dgraph-io/badger/v4"
+import (
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/rawbytedev/zerokv"
+)
 
 // specific badgerdb options
 type Config struct {
 	Dir           string
 	BadgerConfigs *badger.Options
+	// Retry controls how Update retries on conflicts, nil uses zerokv.DefaultRetryPolicy
+	Retry *zerokv.RetryPolicy
+	// Clock is used for TTL expiry, nil uses time.Now
+	Clock func() time.Time
 }
 
 func DefaultOptions(Dir string) *Config {
-	return &Config{Dir, nil}
+	return &Config{Dir: Dir}
 }
@@ -0,0 +1,179 @@
+package badgerdb
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/rawbytedev/zerokv"
+)
+
+/*
+badger.WriteBatch cannot be read, so badgerBatch keeps the last staged write of
+every key in an overlay. Batch reads check the overlay first and fall back to the
+database, scans merge a sorted copy of the overlay with a database iterator.
+*/
+
+// pendingWrite is the last operation staged for a key.
+type pendingWrite struct {
+	value     []byte
+	expiresAt time.Time
+	deleted   bool
+}
+
+// pendingEntry is a pendingWrite captured by a batch iterator.
+type pendingEntry struct {
+	key []byte
+	pendingWrite
+}
+
+// Get retrieves the value for key, operations staged in the batch included.
+func (b *badgerBatch) Get(key []byte) ([]byte, error) {
+	if b.done {
+		return nil, zerokv.ErrBatchCommitted
+	}
+	if err := b.db.check(context.Background()); err != nil {
+		return nil, err
+	}
+	if w, ok := b.pending[string(key)]; ok {
+		if w.deleted || b.db.expiredAt(w.expiresAt) {
+			return nil, zerokv.ErrNotFound
+		}
+		return copyBytes(w.value), nil
+	}
+	if inRanges(b.ranges, key) {
+		return nil, zerokv.ErrNotFound
+	}
+	var data []byte
+	err := b.db.db.View(func(txn *badger.Txn) error {
+		var err error
+		data, err = b.db.getValue(txn, key)
+		return err
+	})
+	return data, err
+}
+
+// Scan iterates over keys starting with prefix, operations staged in the batch included.
+func (b *badgerBatch) Scan(prefix []byte) zerokv.Iterator {
+	if b.done {
+		return zerokv.NewErrIterator(zerokv.ErrBatchCommitted)
+	}
+	if err := b.db.check(context.Background()); err != nil {
+		return zerokv.NewErrIterator(err)
+	}
+	// the overlay is captured now, writes staged later are not seen by the iterator
+	var pending []pendingEntry
+	for key, w := range b.pending {
+		if bytes.HasPrefix([]byte(key), prefix) {
+			pending = append(pending, pendingEntry{key: []byte(key), pendingWrite: w})
+		}
+	}
+	slices.SortFunc(pending, func(a, b pendingEntry) int { return bytes.Compare(a.key, b.key) })
+	base := b.db.newIterator(context.Background(), b.db.db.NewTransaction(false), true, zerokv.ScanOptions{Prefix: prefix})
+	return &batchIterator{db: b.db, base: base, pending: pending, ranges: slices.Clone(b.ranges)}
+}
+
+// batchIterator merges the overlay of a batch over a database iterator, the overlay wins on equal keys.
+type batchIterator struct {
+	db      *badgerDB
+	base    *badgerIterator
+	pending []pendingEntry
+	// ranges hide the stored keys they cover
+	ranges []keyRange
+	// next is the index of the first overlay entry not consumed yet
+	next int
+	// baseKey is the key base is positioned on, nil once it is exhausted
+	baseKey []byte
+	key     []byte
+	value   []byte
+	started bool
+	valid   bool
+}
+
+func (it *batchIterator) Next() bool {
+	if !it.started {
+		return it.First()
+	}
+	return it.settle()
+}
+
+// First moves back to the first entry of the scan.
+func (it *batchIterator) First() bool {
+	it.started, it.next = true, 0
+	it.moveBase(it.base.First())
+	return it.settle()
+}
+
+// Seek moves to the first key >= key.
+func (it *batchIterator) Seek(key []byte) bool {
+	it.started = true
+	it.next = sort.Search(len(it.pending), func(i int) bool {
+		return bytes.Compare(it.pending[i].key, key) >= 0
+	})
+	it.moveBase(it.base.Seek(key))
+	return it.settle()
+}
+
+// moveBase records the key base landed on.
+func (it *batchIterator) moveBase(valid bool) {
+	it.baseKey = nil
+	if valid {
+		it.baseKey = it.base.Key()
+	}
+}
+
+// settle consumes the smallest of the next overlay and database entries,
+// deleted and expired overlay entries hide the database entry they shadow.
+func (it *batchIterator) settle() bool {
+	for {
+		hasPending := it.next < len(it.pending)
+		if !hasPending && it.baseKey == nil {
+			it.valid = false
+			return false
+		}
+		if !hasPending || it.baseKey != nil && bytes.Compare(it.baseKey, it.pending[it.next].key) < 0 {
+			if inRanges(it.ranges, it.baseKey) {
+				it.moveBase(it.base.Next())
+				continue
+			}
+			it.key, it.value, it.valid = it.baseKey, it.base.Value(), true
+			it.moveBase(it.base.Next())
+			return true
+		}
+		entry := it.pending[it.next]
+		it.next++
+		if it.baseKey != nil && bytes.Equal(it.baseKey, entry.key) {
+			it.moveBase(it.base.Next())
+		}
+		if !entry.deleted && !it.db.expiredAt(entry.expiresAt) {
+			it.key, it.value, it.valid = entry.key, entry.value, true
+			return true
+		}
+	}
+}
+
+func (it *batchIterator) Key() []byte {
+	if !it.valid {
+		return nil
+	}
+	return copyBytes(it.key)
+}
+
+func (it *batchIterator) Value() []byte {
+	if !it.valid {
+		return nil
+	}
+	return copyBytes(it.value)
+}
+
+func (it *batchIterator) Release() {
+	it.valid = false
+	it.base.Release()
+}
+
+func (it *batchIterator) Error() error {
+	return it.base.Error()
+}
@@ -0,0 +1,49 @@
+package badgerdb
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+
+	"github.com/dgraph-io/badger/v4/y"
+	"github.com/rawbytedev/zerokv"
+)
+
+/*
+Partitions follow table boundaries the way badger's Stream framework splits its
+work: the right key of every table in range is a candidate split, weighted with
+the table's on-disk size. Keys only in the memtables are not estimated, a store
+without tables in range is split evenly between its first and last key.
+*/
+
+// ParallelScan scans prefix in partitions of about the same disk usage, with workers goroutines.
+func (b *badgerDB) ParallelScan(ctx context.Context, prefix []byte, workers int, fn zerokv.ScanFunc) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	splits, err := b.splits(ctx, prefix, workers*zerokv.PartitionsPerWorker)
+	if err != nil {
+		return err
+	}
+	return zerokv.ScanPartitions(ctx, b, prefix, splits, workers, fn)
+}
+
+// splits returns up to n-1 split keys under prefix.
+func (b *badgerDB) splits(ctx context.Context, prefix []byte, n int) ([][]byte, error) {
+	lower, upper := zerokv.ScanOptions{Prefix: prefix}.Bounds()
+	var points []zerokv.SplitPoint
+	for _, t := range b.db.Tables() {
+		// table keys carry the version suffix
+		key := y.ParseKey(t.Right)
+		if bytes.Compare(key, lower) > 0 && (upper == nil || bytes.Compare(key, upper) < 0) {
+			points = append(points, zerokv.SplitPoint{Key: bytes.Clone(key), Size: uint64(t.OnDiskSize)})
+		}
+	}
+	if splits := zerokv.SizedSplits(points, n); len(splits) > 0 {
+		return splits, nil
+	}
+	return zerokv.EvenSplits(ctx, b, prefix, n)
+}
@@ -0,0 +1,42 @@
+package badgerdb
+
+import (
+	"context"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/rawbytedev/zerokv"
+)
+
+type badgerSnapshot struct {
+	db  *badgerDB
+	txn *badger.Txn
+}
+
+// Snapshot captures a read-only badger.Txn, which reads at a fixed timestamp.
+func (b *badgerDB) Snapshot() (zerokv.Snapshot, error) {
+	if b.closed.Load() {
+		return nil, zerokv.ErrClosed
+	}
+	return &badgerSnapshot{db: b, txn: b.db.NewTransaction(false)}, nil
+}
+
+// Get retrieves the value for key as of the snapshot.
+func (s *badgerSnapshot) Get(ctx context.Context, key []byte) ([]byte, error) {
+	if err := s.db.check(ctx); err != nil {
+		return nil, err
+	}
+	return s.db.getValue(s.txn, key)
+}
+
+// Scan iterates over keys starting with prefix as of the snapshot.
+func (s *badgerSnapshot) Scan(prefix []byte) zerokv.Iterator {
+	if err := s.db.check(context.Background()); err != nil {
+		return zerokv.NewErrIterator(err)
+	}
+	return s.db.newIterator(context.Background(), s.txn, false, zerokv.ScanOptions{Prefix: prefix})
+}
+
+// Release discards the read-only transaction.
+func (s *badgerSnapshot) Release() {
+	s.txn.Discard()
+}
@@ -0,0 +1,117 @@
+package badgerdb
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/rawbytedev/zerokv"
+)
+
+/*
+Badger expires entries natively through Entry.ExpiresAt, in whole seconds of the
+wall clock. To expire at the exact instant like other backends, expiring values
+are flagged in UserMeta and start with their expiry:
+
+	expiry (8 bytes, big-endian unix nanoseconds) | payload
+
+Reads filter items against the configured clock, so an injected clock running
+ahead of the wall clock expires keys deterministically. The header is only read
+during the last second before ExpiresAt, earlier or later the seconds decide.
+Entries written without the flag keep whole second expiry.
+*/
+
+const (
+	// metaExactExpiry flags entries whose value starts with their exact expiry.
+	metaExactExpiry byte = 1 << 0
+	expiryLen            = 8
+)
+
+// PutWithTTL inserts or updates a key-value pair that expires after ttl.
+func (b *badgerDB) PutWithTTL(ctx context.Context, key, value []byte, ttl time.Duration) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	err := b.db.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(b.newEntry(key, value, b.expiry(ttl)))
+	})
+	if err != nil {
+		return convertError(err)
+	}
+	return b.syncWrite(nil)
+}
+
+// PutWithTTL inserts or updates a key-value pair in the batch that expires after ttl.
+func (b *badgerBatch) PutWithTTL(key, value []byte, ttl time.Duration) error {
+	return b.set(key, value, b.db.expiry(ttl))
+}
+
+// expiry returns the absolute expiry for ttl, zero when ttl is not positive.
+func (b *badgerDB) expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return b.clock().Add(ttl)
+}
+
+// newEntry builds an entry expiring at expiresAt, the zero time never expires.
+func (b *badgerDB) newEntry(key, value []byte, expiresAt time.Time) *badger.Entry {
+	if expiresAt.IsZero() {
+		return badger.NewEntry(key, value)
+	}
+	raw := make([]byte, expiryLen+len(value))
+	binary.BigEndian.PutUint64(raw, uint64(expiresAt.UnixNano()))
+	copy(raw[expiryLen:], value)
+	e := badger.NewEntry(key, raw).WithMeta(metaExactExpiry)
+	// badger stores whole seconds, round up so an entry never expires early
+	e.ExpiresAt = uint64(expiresAt.Unix())
+	if expiresAt.Nanosecond() > 0 {
+		e.ExpiresAt++
+	}
+	return e
+}
+
+// payload strips the expiry header from the value of item.
+func payload(item *badger.Item, val []byte) ([]byte, error) {
+	if item.UserMeta()&metaExactExpiry == 0 {
+		return val, nil
+	}
+	if len(val) < expiryLen {
+		return nil, fmt.Errorf("%w: short badger expiry header", zerokv.ErrCorruptValue)
+	}
+	return val[expiryLen:], nil
+}
+
+// expired reports whether item is past its expiry according to the configured clock.
+func (b *badgerDB) expired(item *badger.Item) bool {
+	secs := item.ExpiresAt()
+	if secs == 0 {
+		return false
+	}
+	now := b.clock()
+	if secs <= uint64(now.Unix()) {
+		return true
+	}
+	if item.UserMeta()&metaExactExpiry == 0 || int64(secs)-1 > now.Unix() {
+		return false
+	}
+	// within the last second the exact expiry decides, an unreadable one is left to the seconds
+	var exact int64
+	_ = item.Value(func(val []byte) error {
+		if len(val) >= expiryLen {
+			exact = int64(binary.BigEndian.Uint64(val))
+		}
+		return nil
+	})
+	return exact != 0 && exact <= now.UnixNano()
+}
+
+// expiredAt reports whether expiresAt has passed, the zero time never expires.
+func (b *badgerDB) expiredAt(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && !expiresAt.After(b.clock())
+}
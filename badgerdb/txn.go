@@ -0,0 +1,75 @@
+package badgerdb
+
+import (
+	"context"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/rawbytedev/zerokv"
+)
+
+type badgerTxn struct {
+	db  *badgerDB
+	txn *badger.Txn
+	// wrote is set once a write is staged, only then the commit is synced
+	wrote bool
+}
+
+// Update runs fn in a badger read-write transaction, retrying on badger.ErrConflict.
+// A transaction that wrote is synced like every default write.
+func (b *badgerDB) Update(ctx context.Context, fn func(tx zerokv.Txn) error) error {
+	var wrote bool
+	err := b.retry.Do(ctx, func() error {
+		if err := b.check(ctx); err != nil {
+			return err
+		}
+		return convertError(b.db.Update(func(txn *badger.Txn) error {
+			tx := &badgerTxn{db: b, txn: txn}
+			err := fn(tx)
+			wrote = tx.wrote
+			return err
+		}))
+	})
+	if err != nil || !wrote {
+		return err
+	}
+	return b.syncWrite(nil)
+}
+
+// View runs fn in a badger read-only transaction.
+func (b *badgerDB) View(ctx context.Context, fn func(tx zerokv.Txn) error) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	return convertError(b.db.View(func(txn *badger.Txn) error {
+		return fn(&badgerTxn{db: b, txn: txn})
+	}))
+}
+
+// Get retrieves the value for key, pending writes of the transaction included.
+func (t *badgerTxn) Get(key []byte) ([]byte, error) {
+	return t.db.getValue(t.txn, key)
+}
+
+// Put stages an insert or update in the transaction.
+func (t *badgerTxn) Put(key []byte, data []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	t.wrote = true
+	// badger keeps references until commit
+	return convertError(t.txn.Set(copyBytes(key), copyBytes(data)))
+}
+
+// Delete stages a delete in the transaction.
+func (t *badgerTxn) Delete(key []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	t.wrote = true
+	return convertError(t.txn.Delete(copyBytes(key)))
+}
+
+// Scan iterates over keys starting with prefix, pending writes of the transaction included.
+func (t *badgerTxn) Scan(prefix []byte) zerokv.Iterator {
+	return t.db.newIterator(context.Background(), t.txn, false, zerokv.ScanOptions{Prefix: prefix})
+}
@@ -0,0 +1,131 @@
+package zerokv
+
+import (
+	"bytes"
+	"context"
+)
+
+// Default BatchWriter thresholds, they keep sub-batches well below badger's transaction limits.
+const (
+	DefaultBatchMaxOps   = 10000
+	DefaultBatchMaxBytes = 4 << 20
+)
+
+// BatchWriterOptions controls when a BatchWriter flushes its sub-batch.
+type BatchWriterOptions struct {
+	// MaxOps flushes once this many operations are staged, 0 uses DefaultBatchMaxOps.
+	MaxOps int
+	// MaxBytes flushes once the staged keys and values reach this size, 0 uses DefaultBatchMaxBytes.
+	MaxBytes int
+	// OnFlush is called after every committed sub-batch with the progress so far.
+	OnFlush func(BatchProgress)
+}
+
+// BatchProgress reports what a BatchWriter has committed so far.
+type BatchProgress struct {
+	// Batches is the number of sub-batches committed.
+	Batches int
+	// Ops is the number of operations committed.
+	Ops int
+	// Bytes is the total size of the keys and values committed.
+	Bytes int
+	// LastKey is the last key written by the most recent committed sub-batch, nil before the first one.
+	// Loads that write keys in order resume right after it.
+	LastKey []byte
+}
+
+// BatchWriter accepts any number of writes and commits them through sub-batches
+// small enough for the backend.
+/*
+	Writes are not atomic as a whole: every sub-batch is committed on its own once a
+	threshold is hit. Like bufio.Writer, once a sub-batch fails to commit every later
+	call returns that error, Progress tells what was written before it.
+	A BatchWriter is not safe for concurrent use.
+*/
+type BatchWriter struct {
+	batch    Batch
+	opts     BatchWriterOptions
+	progress BatchProgress
+	// lastKey is the last key staged in the current sub-batch
+	lastKey []byte
+	err     error
+}
+
+// NewBatchWriter returns a BatchWriter committing through batches of core.
+func NewBatchWriter(core Core, opts BatchWriterOptions) *BatchWriter {
+	if opts.MaxOps <= 0 {
+		opts.MaxOps = DefaultBatchMaxOps
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultBatchMaxBytes
+	}
+	return &BatchWriter{batch: core.Batch(), opts: opts}
+}
+
+// Put stages a key-value pair, flushing the sub-batch when it is full.
+func (w *BatchWriter) Put(ctx context.Context, key []byte, data []byte) error {
+	return w.write(ctx, key, func() error { return w.batch.Put(key, data) })
+}
+
+// Delete stages a delete, flushing the sub-batch when it is full.
+func (w *BatchWriter) Delete(ctx context.Context, key []byte) error {
+	return w.write(ctx, key, func() error { return w.batch.Delete(key) })
+}
+
+// write stages one operation through stage and flushes once a threshold is reached.
+func (w *BatchWriter) write(ctx context.Context, key []byte, stage func() error) error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := stage(); err != nil {
+		return err
+	}
+	w.lastKey = append(w.lastKey[:0], key...)
+	if w.batch.Len() >= w.opts.MaxOps || w.batch.ByteSize() >= w.opts.MaxBytes {
+		return w.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush commits the staged operations as one sub-batch.
+func (w *BatchWriter) Flush(ctx context.Context) error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if w.batch.Len() == 0 {
+		return nil
+	}
+	ops, size := w.batch.Len(), w.batch.ByteSize()
+	if err := w.batch.Commit(ctx); err != nil {
+		w.err = err
+		return err
+	}
+	w.progress.Batches++
+	w.progress.Ops += ops
+	w.progress.Bytes += size
+	w.progress.LastKey = bytes.Clone(w.lastKey)
+	w.batch.Reset()
+	if w.opts.OnFlush != nil {
+		w.opts.OnFlush(w.Progress())
+	}
+	return nil
+}
+
+// Close flushes the remaining operations and releases the underlying batch.
+func (w *BatchWriter) Close(ctx context.Context) error {
+	defer w.batch.Discard()
+	return w.Flush(ctx)
+}
+
+// Progress returns what has been committed so far, LastKey is owned by the caller.
+func (w *BatchWriter) Progress() BatchProgress {
+	progress := w.progress
+	progress.LastKey = bytes.Clone(progress.LastKey)
+	return progress
+}
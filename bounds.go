@@ -0,0 +1,33 @@
+package zerokv
+
+import "bytes"
+
+// PrefixUpperBound returns the smallest key greater than every key starting with prefix.
+// It returns nil when no such key exists, that is for an empty prefix or one made only of 0xFF bytes.
+func PrefixUpperBound(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xFF {
+			upper := make([]byte, i+1)
+			copy(upper, prefix)
+			upper[i]++
+			return upper
+		}
+	}
+	return nil
+}
+
+// Bounds returns the effective [lower, upper) range of the scan once Prefix is merged with Start and End.
+// A nil bound means the scan is open on that side.
+func (o ScanOptions) Bounds() (lower, upper []byte) {
+	lower, upper = o.Start, o.End
+	if len(o.Prefix) == 0 {
+		return lower, upper
+	}
+	if lower == nil || bytes.Compare(lower, o.Prefix) < 0 {
+		lower = o.Prefix
+	}
+	if pu := PrefixUpperBound(o.Prefix); pu != nil && (upper == nil || bytes.Compare(pu, upper) < 0) {
+		upper = pu
+	}
+	return lower, upper
+}
@@ -0,0 +1,188 @@
+package zerokv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"time"
+)
+
+/*
+Encoded batches are backend-neutral, so a batch built on one engine can be applied on another:
+
+	magic "ZKVB" | version | operation count (uvarint) | operations | crc32c of everything before (4 bytes, big-endian)
+
+Every operation starts with its Ops type byte, byte strings are uvarint length prefixed:
+
+	PutOp         key | value | expiry (varint unix nanoseconds, 0 never expires)
+	DeleteOp      key
+	DeleteRangeOp start | end present (0 or 1) | end
+*/
+
+const (
+	batchMagic   = "ZKVB"
+	batchVersion = 1
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// EncodeOperations serializes ops in the encoded batch format.
+func EncodeOperations(ops []Operations) []byte {
+	buf := append([]byte(batchMagic), batchVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(ops)))
+	for _, op := range ops {
+		buf = append(buf, byte(op.Type))
+		buf = appendBytes(buf, op.Key)
+		switch op.Type {
+		case PutOp:
+			buf = appendBytes(buf, op.Value)
+			var expiresAt int64
+			if !op.ExpiresAt.IsZero() {
+				expiresAt = op.ExpiresAt.UnixNano()
+			}
+			buf = binary.AppendVarint(buf, expiresAt)
+		case DeleteRangeOp:
+			if op.End == nil {
+				buf = append(buf, 0)
+			} else {
+				buf = appendBytes(append(buf, 1), op.End)
+			}
+		}
+	}
+	return binary.BigEndian.AppendUint32(buf, crc32.Checksum(buf, castagnoli))
+}
+
+// DecodeOperations parses an encoded batch, the returned slices alias data.
+func DecodeOperations(data []byte) ([]Operations, error) {
+	header := len(batchMagic) + 1
+	if len(data) < header+4 || string(data[:len(batchMagic)]) != batchMagic {
+		return nil, fmt.Errorf("%w: missing header", ErrCorruptBatch)
+	}
+	body, sum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if crc32.Checksum(body, castagnoli) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorruptBatch)
+	}
+	if v := body[len(batchMagic)]; v != batchVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrCorruptBatch, v)
+	}
+	d := decoder{buf: body[header:]}
+	count := d.readUvarint()
+	ops := make([]Operations, 0, min(count, uint64(len(d.buf))))
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		op := Operations{Type: Ops(d.readByte())}
+		op.Key = d.readBytes()
+		switch op.Type {
+		case PutOp:
+			op.Value = d.readBytes()
+			if expiresAt := d.readVarint(); expiresAt != 0 {
+				op.ExpiresAt = time.Unix(0, expiresAt)
+			}
+		case DeleteOp:
+		case DeleteRangeOp:
+			if d.readByte() == 1 {
+				op.End = d.readBytes()
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported operation %d", ErrCorruptBatch, op.Type)
+		}
+		ops = append(ops, op)
+	}
+	if d.err == nil && len(d.buf) != 0 {
+		d.err = fmt.Errorf("%w: trailing data", ErrCorruptBatch)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return ops, nil
+}
+
+// ApplyOperations stages ops into b in order.
+/*
+	PutOp expiries are turned back into TTLs relative to now, puts that already
+	expired are staged as deletes since the key must read as missing.
+*/
+func ApplyOperations(b Batch, ops []Operations, now time.Time) error {
+	for _, op := range ops {
+		var err error
+		switch op.Type {
+		case PutOp:
+			switch {
+			case op.ExpiresAt.IsZero():
+				err = b.Put(op.Key, op.Value)
+			case op.ExpiresAt.After(now):
+				err = b.PutWithTTL(op.Key, op.Value, op.ExpiresAt.Sub(now))
+			default:
+				err = b.Delete(op.Key)
+			}
+		case DeleteOp:
+			err = b.Delete(op.Key)
+		case DeleteRangeOp:
+			err = b.DeleteRange(op.Key, op.End)
+		default:
+			err = fmt.Errorf("%w: unsupported operation %d", ErrCorruptBatch, op.Type)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendBytes appends b with its uvarint length.
+func appendBytes(buf, b []byte) []byte {
+	return append(binary.AppendUvarint(buf, uint64(len(b))), b...)
+}
+
+// decoder reads an encoded batch body, the first error sticks and zero values are returned after it.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: truncated data", ErrCorruptBatch)
+	}
+	d.buf = nil
+}
+
+func (d *decoder) readByte() byte {
+	if len(d.buf) < 1 {
+		d.fail()
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *decoder) readUvarint() uint64 {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) readVarint() int64 {
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) readBytes() []byte {
+	n := d.readUvarint()
+	if d.err != nil || n > uint64(len(d.buf)) {
+		d.fail()
+		return nil
+	}
+	b := d.buf[:n:n]
+	d.buf = d.buf[n:]
+	return b
+}
@@ -0,0 +1,63 @@
+package zerokv
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by every zerokv implementation.
+/*
+	Backends translate their native errors into these values so callers can use
+	errors.Is without importing the underlying engine. The original engine error
+	is kept in the chain whenever it carries extra detail.
+*/
+var (
+	// ErrNotFound is returned when a key does not exist.
+	ErrNotFound = errors.New("zerokv: key not found")
+	// ErrClosed is returned when operating on a database that has been closed.
+	ErrClosed = errors.New("zerokv: database closed")
+	// ErrBatchCommitted is returned when using a batch after it has been committed or discarded.
+	ErrBatchCommitted = errors.New("zerokv: batch already committed")
+	// ErrWritesBlocked is returned when the backend refuses writes for a while, such as during maintenance.
+	ErrWritesBlocked = errors.New("zerokv: writes temporarily blocked")
+	// ErrConflict is returned when a transaction conflicts with a concurrent write.
+	ErrConflict = errors.New("zerokv: transaction conflict")
+	// ErrReadOnly is returned when writing through a read-only transaction.
+	ErrReadOnly = errors.New("zerokv: read-only transaction")
+	// ErrEmptyKey is returned when an operation is given an empty key.
+	ErrEmptyKey = errors.New("zerokv: key cannot be empty")
+	// ErrKeyTooLarge is returned when a key exceeds the backend size limit.
+	ErrKeyTooLarge = errors.New("zerokv: key too large")
+	// ErrTxnTooBig is returned when a single write exceeds the backend transaction limit.
+	ErrTxnTooBig = errors.New("zerokv: transaction too big")
+	// ErrLengthMismatch is returned when paired key and value slices differ in length.
+	ErrLengthMismatch = errors.New("zerokv: keys and values length mismatch")
+	// ErrCorruptBatch is returned when an encoded batch cannot be decoded.
+	ErrCorruptBatch = errors.New("zerokv: corrupt encoded batch")
+	// ErrInvalidSavepoint is returned when rolling a batch back to a savepoint it does not have.
+	ErrInvalidSavepoint = errors.New("zerokv: invalid savepoint")
+	// ErrInvalidToken is returned when a pagination token was altered or belongs to another scan.
+	ErrInvalidToken = errors.New("zerokv: invalid page token")
+	// ErrCorruptValue is returned when a stored value cannot be decoded by its backend.
+	ErrCorruptValue = errors.New("zerokv: corrupt stored value")
+	// ErrIncompatibleFormat is returned when opening a store written in a format the backend cannot read as is.
+	ErrIncompatibleFormat = errors.New("zerokv: incompatible storage format")
+)
+
+// IsNotFound reports whether err indicates a missing key.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsRetryable reports whether the operation that returned err may succeed if retried.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrConflict) || errors.Is(err, ErrWritesBlocked)
+}
+
+// Wrap attaches a zerokv sentinel to a backend error, keeping both in the chain.
+func Wrap(sentinel, err error) error {
+	if err == nil || errors.Is(err, sentinel) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", sentinel, err)
+}
@@ -2,7 +2,9 @@ package helpers
 
 import (
 	"crypto/rand"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/rawbytedev/zerokv"
 	"github.com/rawbytedev/zerokv/badgerdb"
@@ -10,17 +12,24 @@ import (
 )
 
 // setupBadgerDB creates a temporary BadgerDB instance for testing.
-func SetupDB(t *testing.T, name string) zerokv.Core {
+func SetupDB(t testing.TB, name string) zerokv.Core {
+	return SetupDBWithClock(t, name, nil)
+}
+
+// SetupDBWithClock creates a temporary database whose TTL expiry follows clock, nil uses time.Now.
+func SetupDBWithClock(t testing.TB, name string, clock func() time.Time) zerokv.Core {
 	tmp := t.TempDir()
 	var db zerokv.Core
 	var err error
 	if name == "badgerdb" {
 		db, err = badgerdb.NewBadgerDB(badgerdb.Config{
-			Dir: tmp,
+			Dir:   tmp,
+			Clock: clock,
 		})
 	} else {
 		db, err = pebbledb.NewPebbleDB(pebbledb.Config{
-			Dir: tmp,
+			Dir:   tmp,
+			Clock: clock,
 		})
 	}
 	if err != nil || db == nil {
@@ -29,9 +38,49 @@ func SetupDB(t *testing.T, name string) zerokv.Core {
 	return db
 }
 
+// OpenDB opens, or reopens, a database stored in dir.
+func OpenDB(t testing.TB, name, dir string) zerokv.Core {
+	var db zerokv.Core
+	var err error
+	if name == "badgerdb" {
+		db, err = badgerdb.NewBadgerDB(badgerdb.Config{Dir: dir})
+	} else {
+		db, err = pebbledb.NewPebbleDB(pebbledb.Config{Dir: dir})
+	}
+	if err != nil || db == nil {
+		t.Fatalf("Failed to open %s: %v", name, err)
+	}
+	return db
+}
+
 // randomBytes generates a slice of random bytes of specified length.
 func RandomBytes(n int) []byte {
 	b := make([]byte, n)
 	rand.Read(b)
 	return b
 }
+
+// FakeClock is a manually advanced clock for TTL tests.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock stopped at the current time.
+func NewFakeClock() *FakeClock {
+	return &FakeClock{now: time.Now()}
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d, a negative d moves it back.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -1,14 +1,54 @@
 package zerokv
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// Core is the key-value store abstraction implemented by every backend.
+/*
+	Ownership: slices passed to Core, Batch and Iterator methods may be reused by the caller
+	once the call returns, and slices returned by them belong to the caller, they are never
+	reused or modified by the backend. Only explicit zero-copy APIs hand out engine-owned memory.
+*/
 type Core interface {
 	// Put inserts or updates a key-value pair in the database.
-	Put(ctx context.Context, key []byte, data []byte) error
+	// Without opts the write is synced before Put returns, see WriteOptions.
+	Put(ctx context.Context, key []byte, data []byte, opts ...WriteOptions) error
+	// PutWithTTL inserts or updates a key-value pair that expires after ttl.
+	// Expired keys behave as missing, a non-positive ttl never expires.
+	PutWithTTL(ctx context.Context, key []byte, data []byte, ttl time.Duration) error
 	// Get retrieves the value for a given key.
+	// The returned slice is owned by the caller and stays valid after later operations.
 	Get(ctx context.Context, key []byte) ([]byte, error)
+	// GetFunc calls fn with the value for key without copying it.
+	// val is owned by the engine and only valid until fn returns.
+	GetFunc(ctx context.Context, key []byte, fn func(val []byte) error) error
+	// GetMany retrieves several keys from one consistent view.
+	// errs[i] is ErrNotFound when keys[i] is missing, values[i] is then nil.
+	GetMany(ctx context.Context, keys [][]byte) (values [][]byte, errs []error)
+	// PutMany atomically inserts or updates keys[i] with values[i] through a single batch.
+	PutMany(ctx context.Context, keys [][]byte, values [][]byte) error
+	// Has reports whether key exists without reading its value.
+	Has(ctx context.Context, key []byte) (bool, error)
+	// HasMany reports for each key whether it exists, all keys are checked against the same view.
+	HasMany(ctx context.Context, keys [][]byte) ([]bool, error)
 	// Del deletes a key-value pair from the database.
-	Delete(ctx context.Context, key []byte) error
+	// Without opts the delete is synced before Delete returns, see WriteOptions.
+	Delete(ctx context.Context, key []byte, opts ...WriteOptions) error
+	// DeleteRange deletes every key in [start, end), a nil bound leaves that side open.
+	/*
+		Pebble drops the range with one atomic range tombstone. Badger deletes the keys one by
+		one through a write batch that commits in parts, so readers may see a partly deleted
+		range, a failed call may leave one behind and keys written during the call may survive.
+	*/
+	DeleteRange(ctx context.Context, start, end []byte) error
+	// DeletePrefix deletes every key starting with prefix, an empty prefix is rejected with ErrEmptyKey.
+	// It is DeleteRange over the prefix and is as atomic as DeleteRange on each backend.
+	DeletePrefix(ctx context.Context, prefix []byte) error
+	// ApplyEncodedBatch commits, through a single batch, the operations of a Batch.Encode from any backend.
+	// Data that is truncated, corrupted or of an unknown version fails with ErrCorruptBatch.
+	ApplyEncodedBatch(ctx context.Context, data []byte) error
 	// Batch Operation creates a new batch operation for the database.
 	/*
 		Must be used carefully calling Batch creates a new write batch that needs to be committed separately or else it may lead to uncommitted data and data loss.
@@ -16,13 +56,100 @@ type Core interface {
 	Batch() Batch
 	// Iterate over Database
 	Scan(prefix []byte) Iterator
+	// ScanContext iterates over keys starting with prefix until ctx is done, Error then reports ctx.Err().
+	ScanContext(ctx context.Context, prefix []byte) Iterator
+	// ScanKeys iterates over keys starting with prefix without loading values, Value returns nil.
+	ScanKeys(ctx context.Context, prefix []byte) Iterator
+	// ScanRange iterates over keys within the bounds described by opts.
+	ScanRange(ctx context.Context, opts ScanOptions) Iterator
+	// ForEach calls fn for every key starting with prefix, in order, without copying.
+	// key and val are owned by the engine and only valid until fn returns, an error from fn stops the scan.
+	ForEach(ctx context.Context, prefix []byte, fn func(key, val []byte) error) error
+	// ParallelScan calls fn for every key starting with prefix, scanning partitions of the prefix with workers goroutines.
+	// fn is called concurrently and keys arrive in order only within a partition, the first error stops every worker.
+	ParallelScan(ctx context.Context, prefix []byte, workers int, fn ScanFunc) error
+	// CompareAndSwap atomically replaces the value of key with new if it currently equals old.
+	// A nil old means the key must be absent. It reports whether the swap happened.
+	CompareAndSwap(ctx context.Context, key []byte, old []byte, new []byte) (bool, error)
+	// PutIfAbsent atomically stores value only if key does not exist and reports whether it did.
+	PutIfAbsent(ctx context.Context, key []byte, value []byte) (bool, error)
+	// DeleteIfEqual atomically deletes key if its value equals old and reports whether it did.
+	DeleteIfEqual(ctx context.Context, key []byte, old []byte) (bool, error)
+	// UpdateFunc atomically replaces the value of key with the result of fn.
+	// fn receives nil when the key is absent, returning nil deletes the key. fn may write to the
+	// store but not call the atomic helpers, it runs again when key changes before its result is written.
+	UpdateFunc(ctx context.Context, key []byte, fn func(old []byte) ([]byte, error)) error
+	// Update runs fn in a read-write transaction committed when fn returns nil.
+	/*
+		Transactions are optimistic: a commit that conflicts with a concurrent transaction fails
+		with ErrConflict and fn is run again according to the backend RetryPolicy, so fn must be
+		safe to call several times. Iterators opened from tx must be released before fn returns.
+	*/
+	Update(ctx context.Context, fn func(tx Txn) error) error
+	// View runs fn in a read-only transaction, writes through tx fail with ErrReadOnly.
+	View(ctx context.Context, fn func(tx Txn) error) error
+	// Snapshot captures a point-in-time view of the database.
+	// Writes made after the call are invisible through it, Release must be called when done.
+	Snapshot() (Snapshot, error)
+	// Sync makes every write acknowledged so far durable, including writes made without WriteOptions.Sync.
+	Sync(ctx context.Context) error
 	// Close closes the database and releases all resources.
 	Close() error
 }
 
+// Txn is a transaction handed to Update and View, it is only valid until fn returns.
+type Txn interface {
+	// Get retrieves the value for key, including writes made earlier in the transaction.
+	Get(key []byte) ([]byte, error)
+	// Put stages an insert or update committed with the transaction.
+	Put(key []byte, data []byte) error
+	// Delete stages a delete committed with the transaction.
+	Delete(key []byte) error
+	// Scan iterates over keys starting with prefix, including writes made earlier in the transaction.
+	Scan(prefix []byte) Iterator
+}
+
+// Snapshot is a consistent read-only view of the database at the time it was taken.
+type Snapshot interface {
+	// Get retrieves the value for key as of the snapshot.
+	Get(ctx context.Context, key []byte) ([]byte, error)
+	// Scan iterates over keys starting with prefix as of the snapshot.
+	Scan(prefix []byte) Iterator
+	// Release frees the snapshot, iterators opened from it must be released first.
+	Release()
+}
+
+// ScanOptions describes the bounds and behaviour of a range scan.
+type ScanOptions struct {
+	// Start is the inclusive lower bound, nil starts at the first key.
+	Start []byte
+	// End is the exclusive upper bound, nil runs until the last key.
+	End []byte
+	// Prefix restricts the scan to keys starting with Prefix, combined with Start and End.
+	Prefix []byte
+	// Limit caps the number of entries returned, 0 means no limit.
+	Limit int
+	// Reverse iterates from End down to Start.
+	Reverse bool
+	// KeysOnly skips loading values, Value returns nil.
+	// Badger then reads keys without prefetching values and Pebble only looks at value headers.
+	KeysOnly bool
+}
+
 type Iterator interface {
 	Next() bool
+	// Seek moves to the first key >= key, or the last key <= key for reverse scans.
+	/*
+		The entry found is the current one, Next continues after it:
+		for ok := it.Seek(resume); ok; ok = it.Next() { ... }
+		Keys outside the scan bounds are clamped to them. Seek and First restart the scan Limit.
+	*/
+	Seek(key []byte) bool
+	// First moves back to the first entry of the scan, in iteration order.
+	First() bool
+	// Key returns a caller-owned copy of the current key.
 	Key() []byte
+	// Value returns a caller-owned copy of the current value.
 	Value() []byte
 	Release()
 	Error() error
@@ -33,20 +160,66 @@ type Batch interface {
 	/*
 		It is crucial to call Commits to ensure that all batched operations are saved to the database.
 		and no new insertions/updates/deletions will be saved until Commits is called.
-		Inserting to already committed batch is forbidden and will lead to errors.
+		Once committed or discarded every method fails with ErrBatchCommitted until Reset is called.
+		A ctx done before the engine starts writing fails with ctx.Err() and leaves the batch intact,
+		so it can be committed again, once writing has started the commit runs to completion.
+		Badger writes an oversized batch in parts while it is staged: those parts are committed
+		whatever ctx, only the operations still in memory are held back by a cancelled Commit.
+		Without opts the commit is synced before Commit returns, see WriteOptions.
 	*/
-	Commit(ctx context.Context) error
+	Commit(ctx context.Context, opts ...WriteOptions) error
+	// Discard drops the staged operations and releases the batch, it is a no-op after Commit.
+	/*
+		A batch that will not be committed must be discarded, defer Discard right after creating it.
+	*/
+	Discard()
+	// Reset drops the staged operations so the batch can be reused, including after Commit or Discard.
+	Reset()
+	// Len returns the number of operations staged since the batch was created or reset.
+	Len() int
+	// ByteSize returns the total size of the keys and values staged since the batch was created or reset.
+	ByteSize() int
 	// Put inserts or updates a key-value pair in the database.
 	Put(key []byte, data []byte) error
+	// PutWithTTL inserts or updates a key-value pair that expires after ttl.
+	PutWithTTL(key []byte, data []byte, ttl time.Duration) error
 	// Del deletes a key-value pair from the database.
 	Delete(key []byte) error
+	// Get retrieves the value for key, operations staged in the batch take precedence over the database.
+	Get(key []byte) ([]byte, error)
+	// Scan iterates over keys starting with prefix, merging the staged operations over the database.
+	// Operations staged after Scan are not visible to the iterator, which must be released before Commit.
+	Scan(prefix []byte) Iterator
+	// DeleteRange deletes every key in [start, end) when the batch is committed, keys put earlier in the batch included.
+	/*
+		Keys put later in the batch survive, stored keys are found at Commit so keys written
+		before it are covered. On badger the range is as atomic as Core.DeleteRange: Commit
+		deletes the stored keys one by one and may commit them in several parts.
+	*/
+	DeleteRange(start, end []byte) error
+	// DeletePrefix deletes every key starting with prefix when the batch is committed.
+	DeletePrefix(prefix []byte) error
+	// Encode returns the staged operations in the backend-neutral format read by Core.ApplyEncodedBatch.
+	Encode() []byte
+	// Savepoint marks the operations staged so far, RollbackTo returns the batch to that point.
+	Savepoint() int
+	// RollbackTo drops every operation staged after sp was taken.
+	/*
+		Savepoints nest: rolling back to sp keeps the savepoints taken before it, those taken
+		after it must no longer be used. A savepoint the batch never had fails with ErrInvalidSavepoint.
+	*/
+	RollbackTo(sp int) error
 }
 
-// mainly for testing and debugging
+// Operations is a single write staged in a Batch, also used for testing and debugging.
 type Operations struct {
 	Key   []byte
 	Value []byte
-	Type  Ops
+	// End is the exclusive upper bound of a DeleteRangeOp starting at Key, nil is open.
+	End []byte
+	// ExpiresAt is when a PutOp expires, the zero time never expires.
+	ExpiresAt time.Time
+	Type      Ops
 }
 
 type Ops int
@@ -55,4 +228,5 @@ const (
 	PutOp Ops = iota
 	GetOp
 	DeleteOp
+	DeleteRangeOp
 )
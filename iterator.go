@@ -0,0 +1,22 @@
+package zerokv
+
+// errIterator is returned in place of an iterator that could not be created.
+type errIterator struct {
+	err error
+}
+
+// NewErrIterator returns an Iterator that yields nothing and reports err from Error.
+/*
+	Backends return it when a scan cannot be started so callers never receive a nil Iterator.
+*/
+func NewErrIterator(err error) Iterator {
+	return &errIterator{err: err}
+}
+
+func (it *errIterator) Next() bool           { return false }
+func (it *errIterator) Seek(key []byte) bool { return false }
+func (it *errIterator) First() bool          { return false }
+func (it *errIterator) Key() []byte          { return nil }
+func (it *errIterator) Value() []byte        { return nil }
+func (it *errIterator) Release()             {}
+func (it *errIterator) Error() error         { return it.err }
@@ -0,0 +1,151 @@
+package zerokv
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+)
+
+/*
+A continuation token carries the last key of the page it follows and the direction:
+
+	version | reverse (0 or 1) | last key | mac
+
+mac is an HMAC-SHA256 over the prefix and everything before it, truncated to
+pageMacLen bytes, so a token cannot be edited or replayed against another prefix.
+Paging resumes strictly after the last key, keys inserted meanwhile never shift
+the pages that follow.
+*/
+
+const (
+	pageTokenVersion = 1
+	pageMacLen       = 16
+)
+
+// PageItem is one entry of a Page.
+type PageItem struct {
+	Key   []byte
+	Value []byte
+}
+
+// Page is one page of a prefix, Next is empty on the last page.
+type Page struct {
+	Items []PageItem
+	Next  string
+}
+
+// Paginator pages through prefixes with continuation tokens signed by its secret.
+/*
+	Every process that serves the same tokens must share the secret.
+*/
+type Paginator struct {
+	secret []byte
+}
+
+// NewPaginator returns a Paginator signing tokens with secret.
+func NewPaginator(secret []byte) *Paginator {
+	return &Paginator{secret: bytes.Clone(secret)}
+}
+
+// defaultPaginator signs tokens with a random secret, they are only valid within this process.
+var defaultPaginator = func() *Paginator {
+	secret := make([]byte, sha256.Size)
+	rand.Read(secret)
+	return &Paginator{secret: secret}
+}()
+
+// Paginate returns the page of up to pageSize entries under prefix that follows token, in key order.
+// An empty token starts at the first key. Tokens are signed with a per-process secret, use a Paginator
+// to share them between processes.
+func Paginate(ctx context.Context, core Core, prefix []byte, pageSize int, token string) (Page, error) {
+	return defaultPaginator.Paginate(ctx, core, prefix, pageSize, token)
+}
+
+// PaginateReverse is Paginate in descending key order.
+func PaginateReverse(ctx context.Context, core Core, prefix []byte, pageSize int, token string) (Page, error) {
+	return defaultPaginator.PaginateReverse(ctx, core, prefix, pageSize, token)
+}
+
+// Paginate returns the page of up to pageSize entries under prefix that follows token, in key order.
+func (p *Paginator) Paginate(ctx context.Context, core Core, prefix []byte, pageSize int, token string) (Page, error) {
+	return p.page(ctx, core, prefix, pageSize, token, false)
+}
+
+// PaginateReverse returns the page of up to pageSize entries under prefix that follows token, in descending key order.
+func (p *Paginator) PaginateReverse(ctx context.Context, core Core, prefix []byte, pageSize int, token string) (Page, error) {
+	return p.page(ctx, core, prefix, pageSize, token, true)
+}
+
+func (p *Paginator) page(ctx context.Context, core Core, prefix []byte, pageSize int, token string, reverse bool) (Page, error) {
+	if pageSize < 1 {
+		return Page{}, fmt.Errorf("zerokv: page size must be positive, got %d", pageSize)
+	}
+	// one extra entry tells whether another page follows
+	opts := ScanOptions{Prefix: prefix, Limit: pageSize + 1, Reverse: reverse}
+	if token != "" {
+		last, err := p.decodeToken(prefix, token, reverse)
+		if err != nil {
+			return Page{}, err
+		}
+		if reverse {
+			opts.End = last
+		} else {
+			// appending 0x00 gives the smallest key after last
+			opts.Start = append(last, 0)
+		}
+	}
+	it := core.ScanRange(ctx, opts)
+	defer it.Release()
+	var page Page
+	for it.Next() {
+		if len(page.Items) == pageSize {
+			page.Next = p.encodeToken(prefix, page.Items[pageSize-1].Key, reverse)
+			break
+		}
+		page.Items = append(page.Items, PageItem{Key: it.Key(), Value: it.Value()})
+	}
+	if err := it.Error(); err != nil {
+		return Page{}, err
+	}
+	return page, nil
+}
+
+func (p *Paginator) encodeToken(prefix, last []byte, reverse bool) string {
+	raw := []byte{pageTokenVersion, 0}
+	if reverse {
+		raw[1] = 1
+	}
+	raw = append(raw, last...)
+	raw = append(raw, p.mac(prefix, raw)...)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeToken verifies token against prefix and direction and returns the last key it carries.
+func (p *Paginator) decodeToken(prefix []byte, token string, reverse bool) ([]byte, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) < 2+pageMacLen {
+		return nil, ErrInvalidToken
+	}
+	body, mac := raw[:len(raw)-pageMacLen], raw[len(raw)-pageMacLen:]
+	if !hmac.Equal(mac, p.mac(prefix, body)) {
+		return nil, ErrInvalidToken
+	}
+	if body[0] != pageTokenVersion || (body[1] == 1) != reverse {
+		return nil, ErrInvalidToken
+	}
+	return body[2:len(body):len(body)], nil
+}
+
+// mac authenticates body for prefix, the prefix length keeps prefix and body apart.
+func (p *Paginator) mac(prefix, body []byte) []byte {
+	h := hmac.New(sha256.New, p.secret)
+	h.Write(binary.AppendUvarint(nil, uint64(len(prefix))))
+	h.Write(prefix)
+	h.Write(body)
+	return h.Sum(nil)[:pageMacLen]
+}
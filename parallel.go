@@ -0,0 +1,179 @@
+package zerokv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math/bits"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+/*
+A parallel scan cuts the keys under a prefix into contiguous partitions at split
+keys and hands them to a pool of workers, each partition being an ordinary ScanRange.
+Backends pick the split keys from their own size estimates with SizedSplits; when
+nothing is known yet (data still in memory) EvenSplits interpolates them between the
+first and last key, which only needs two seeks.
+
+The key space is cut into PartitionsPerWorker partitions per worker so a worker that
+drew a dense partition does not hold up the others.
+*/
+
+// PartitionsPerWorker is the number of partitions a parallel scan aims to give each worker.
+const PartitionsPerWorker = 4
+
+// ScanFunc receives the entries of a parallel scan, from several goroutines at once.
+// Keys and values are copies owned by fn, an error stops the scan.
+type ScanFunc func(key, value []byte) error
+
+// SplitPoint is a candidate partition boundary and the estimated size of the data since the previous one.
+type SplitPoint struct {
+	Key  []byte
+	Size uint64
+}
+
+// SizedSplits picks up to n-1 keys among points so the partitions between them hold about the same size.
+func SizedSplits(points []SplitPoint, n int) [][]byte {
+	points = slices.Clone(points)
+	slices.SortFunc(points, func(a, b SplitPoint) int {
+		return bytes.Compare(a.Key, b.Key)
+	})
+	var total uint64
+	for _, pt := range points {
+		total += pt.Size
+	}
+	if n < 2 || total == 0 {
+		return nil
+	}
+	var splits [][]byte
+	var sum uint64
+	next := 1
+	for _, pt := range points {
+		if next == n {
+			break
+		}
+		sum += pt.Size
+		if sum*uint64(n) < total*uint64(next) {
+			continue
+		}
+		// several points can share a key when tables overlap
+		if len(splits) == 0 || !bytes.Equal(splits[len(splits)-1], pt.Key) {
+			splits = append(splits, pt.Key)
+		}
+		for next < n && sum*uint64(n) >= total*uint64(next) {
+			next++
+		}
+	}
+	return splits
+}
+
+// EvenSplits returns up to n-1 split keys evenly spaced between the first and last key under prefix.
+func EvenSplits(ctx context.Context, core Core, prefix []byte, n int) ([][]byte, error) {
+	first, err := edgeKey(ctx, core, prefix, false)
+	if first == nil || err != nil {
+		return nil, err
+	}
+	last, err := edgeKey(ctx, core, prefix, true)
+	if err != nil {
+		return nil, err
+	}
+	return interpolate(first, last, n), nil
+}
+
+// edgeKey returns the first, or last when reverse, key under prefix.
+func edgeKey(ctx context.Context, core Core, prefix []byte, reverse bool) ([]byte, error) {
+	it := core.ScanRange(ctx, ScanOptions{Prefix: prefix, Limit: 1, Reverse: reverse, KeysOnly: true})
+	defer it.Release()
+	if it.Next() {
+		return it.Key(), nil
+	}
+	return nil, it.Error()
+}
+
+// interpolate spaces n-1 keys between first and last, reading the 8 bytes after their common prefix as a number.
+func interpolate(first, last []byte, n int) [][]byte {
+	common := 0
+	for common < len(first) && common < len(last) && first[common] == last[common] {
+		common++
+	}
+	window := func(key []byte) uint64 {
+		var buf [8]byte
+		copy(buf[:], key[common:])
+		return binary.BigEndian.Uint64(buf[:])
+	}
+	lo, hi := window(first), window(last)
+	if n < 2 || hi <= lo {
+		return nil
+	}
+	var splits [][]byte
+	var prev uint64
+	for i := 1; i < n; i++ {
+		// (hi-lo)*i/n without overflowing
+		mh, ml := bits.Mul64(hi-lo, uint64(i))
+		step, _ := bits.Div64(mh, ml, uint64(n))
+		if step == 0 || step == prev {
+			continue
+		}
+		prev = step
+		split := binary.BigEndian.AppendUint64(slices.Clone(first[:common]), lo+step)
+		splits = append(splits, split)
+	}
+	return splits
+}
+
+// ScanPartitions scans the keys under prefix cut at splits, in ascending order, with workers goroutines.
+/*
+	fn runs concurrently on different partitions, entries of one partition arrive in
+	key order. The first error from fn or an iterator cancels the other workers and is
+	returned. workers below 1 means runtime.GOMAXPROCS(0).
+*/
+func ScanPartitions(ctx context.Context, core Core, prefix []byte, splits [][]byte, workers int, fn ScanFunc) error {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	parts := make(chan ScanOptions)
+	var wg sync.WaitGroup
+	for range min(workers, len(splits)+1) {
+		wg.Go(func() {
+			for opts := range parts {
+				if err := scanPartition(ctx, core, opts, fn); err != nil {
+					cancel(err)
+				}
+			}
+		})
+	}
+	var lower []byte
+send:
+	for i := 0; i <= len(splits); i++ {
+		opts := ScanOptions{Prefix: prefix, Start: lower}
+		if i < len(splits) {
+			opts.End, lower = splits[i], splits[i]
+		}
+		select {
+		case parts <- opts:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(parts)
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+func scanPartition(ctx context.Context, core Core, opts ScanOptions, fn ScanFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	it := core.ScanRange(ctx, opts)
+	defer it.Release()
+	for it.Next() {
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
@@ -0,0 +1,142 @@
+package pebbledb
+
+import (
+	"bytes"
+	"context"
+	"hash/maphash"
+	"slices"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/rawbytedev/zerokv"
+)
+
+// CompareAndSwap replaces the value of key with new if it equals old, under the key lock.
+func (p *pebbleDB) CompareAndSwap(ctx context.Context, key []byte, old []byte, new []byte) (bool, error) {
+	return p.modify(ctx, key, func(cur []byte, found bool) ([]byte, zerokv.Ops, error) {
+		if found != (old != nil) || !bytes.Equal(cur, old) {
+			return nil, zerokv.GetOp, nil
+		}
+		return new, zerokv.PutOp, nil
+	})
+}
+
+// PutIfAbsent stores value only if key does not exist, under the key lock.
+func (p *pebbleDB) PutIfAbsent(ctx context.Context, key []byte, value []byte) (bool, error) {
+	return p.modify(ctx, key, func(cur []byte, found bool) ([]byte, zerokv.Ops, error) {
+		if found {
+			return nil, zerokv.GetOp, nil
+		}
+		return value, zerokv.PutOp, nil
+	})
+}
+
+// DeleteIfEqual deletes key if its value equals old, under the key lock.
+func (p *pebbleDB) DeleteIfEqual(ctx context.Context, key []byte, old []byte) (bool, error) {
+	return p.modify(ctx, key, func(cur []byte, found bool) ([]byte, zerokv.Ops, error) {
+		if !found || !bytes.Equal(cur, old) {
+			return nil, zerokv.GetOp, nil
+		}
+		return nil, zerokv.DeleteOp, nil
+	})
+}
+
+// UpdateFunc replaces the value of key with the result of fn, under the key lock.
+func (p *pebbleDB) UpdateFunc(ctx context.Context, key []byte, fn func(old []byte) ([]byte, error)) error {
+	_, err := p.modify(ctx, key, func(cur []byte, found bool) ([]byte, zerokv.Ops, error) {
+		next, err := fn(cur)
+		switch {
+		case err != nil:
+			return nil, zerokv.GetOp, err
+		case next != nil:
+			return next, zerokv.PutOp, nil
+		case found:
+			return nil, zerokv.DeleteOp, nil
+		}
+		return nil, zerokv.GetOp, nil
+	})
+	return err
+}
+
+// modify reads key and applies the operation chosen by fn while holding the stripe lock of key,
+// so atomic helpers on one key run one at a time while fn is free to write to the store.
+/*
+	Plain writes do not take stripe locks: the write is applied under writeMu held exclusively
+	only if key still holds the value fn saw, otherwise fn runs again according to the
+	RetryPolicy and ErrConflict is returned once it is exhausted. fn must not call the atomic
+	helpers itself. zerokv.GetOp leaves the key untouched, modify reports whether a write happened.
+*/
+func (p *pebbleDB) modify(ctx context.Context, key []byte, fn func(cur []byte, found bool) ([]byte, zerokv.Ops, error)) (bool, error) {
+	if err := p.check(ctx); err != nil {
+		return false, err
+	}
+	if len(key) == 0 {
+		return false, zerokv.ErrEmptyKey
+	}
+	unlock := p.lockKeys([][]byte{key})
+	defer unlock()
+	var written bool
+	err := p.retry.Do(ctx, func() error {
+		cur, found, err := p.current(key)
+		if err != nil {
+			return err
+		}
+		next, op, err := fn(cur, found)
+		if err != nil || op == zerokv.GetOp {
+			return err
+		}
+		if err := p.writeIfUnchanged(key, cur, found, next, op); err != nil {
+			return err
+		}
+		written = true
+		return nil
+	})
+	return written, err
+}
+
+// current returns the live value of key and whether it exists.
+func (p *pebbleDB) current(key []byte) ([]byte, bool, error) {
+	cur, err := p.getValue(p.db, key)
+	if zerokv.IsNotFound(err) {
+		return nil, false, nil
+	}
+	return cur, err == nil, err
+}
+
+// writeIfUnchanged applies op to key if it still holds cur, or fails with ErrConflict.
+func (p *pebbleDB) writeIfUnchanged(key, cur []byte, found bool, next []byte, op zerokv.Ops) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	if p.closed.Load() {
+		return zerokv.ErrClosed
+	}
+	now, exists, err := p.current(key)
+	if err != nil {
+		return err
+	}
+	if exists != found || !bytes.Equal(now, cur) {
+		return zerokv.ErrConflict
+	}
+	if op == zerokv.DeleteOp {
+		return convertError(p.db.Delete(key, pebble.Sync))
+	}
+	return convertError(p.db.Set(key, encodeValue(next, time.Time{}), pebble.Sync))
+}
+
+// lockKeys locks the stripes guarding keys in a fixed order and returns the matching unlock.
+func (p *pebbleDB) lockKeys(keys [][]byte) func() {
+	stripes := make([]int, 0, len(keys))
+	for _, key := range keys {
+		stripes = append(stripes, int(maphash.Bytes(p.seed, key)%uint64(len(p.stripes))))
+	}
+	slices.Sort(stripes)
+	stripes = slices.Compact(stripes)
+	for _, i := range stripes {
+		p.stripes[i].Lock()
+	}
+	return func() {
+		for _, i := range stripes {
+			p.stripes[i].Unlock()
+		}
+	}
+}
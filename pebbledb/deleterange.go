@@ -0,0 +1,115 @@
+package pebbledb
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/rawbytedev/zerokv"
+)
+
+/*
+Deletes map to pebble range tombstones, so dropping a range costs a single
+write whatever its size. Tombstones need a concrete end key: an open range
+ends just after the largest key stored or put through the batch. The end found
+when the range is staged serves batch reads, Commit finds it again under writeMu
+held exclusively and rebuilds the batch, so keys written in between are covered.
+*/
+
+// DeleteRange deletes every key in [start, end) with one range tombstone.
+func (p *pebbleDB) DeleteRange(ctx context.Context, start, end []byte) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
+	batch := p.Batch()
+	defer batch.Discard()
+	if err := batch.DeleteRange(start, end); err != nil {
+		return err
+	}
+	return batch.Commit(ctx)
+}
+
+// DeletePrefix deletes every key starting with prefix with one range tombstone.
+func (p *pebbleDB) DeletePrefix(ctx context.Context, prefix []byte) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
+	if len(prefix) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	return p.DeleteRange(ctx, prefix, zerokv.PrefixUpperBound(prefix))
+}
+
+// DeleteRange stages a range tombstone over [start, end).
+func (p *pebbleBatch) DeleteRange(start, end []byte) error {
+	if p.done {
+		return zerokv.ErrBatchCommitted
+	}
+	// an open end is recorded as is, it resolves against the database the batch is applied to
+	op := zerokv.Operations{Key: copyBytes(start), Type: zerokv.DeleteRangeOp}
+	if end != nil {
+		op.End = copyBytes(end)
+	}
+	// the op is recorded even when there is nothing to delete yet, Encode and Commit must carry it
+	p.record(op)
+	return p.stage(p.batch, op)
+}
+
+// DeletePrefix stages a range tombstone over every key starting with prefix.
+func (p *pebbleBatch) DeletePrefix(prefix []byte) error {
+	if len(prefix) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	return p.DeleteRange(prefix, zerokv.PrefixUpperBound(prefix))
+}
+
+// stageRange stages a tombstone for a DeleteRangeOp on batch, resolving an open end now.
+func (p *pebbleBatch) stageRange(batch *pebble.Batch, op zerokv.Operations) error {
+	end := op.End
+	if end == nil {
+		var err error
+		if end, err = p.rangeEnd(op.Key); err != nil || end == nil {
+			return err
+		}
+	}
+	// formatKey sits below every key callers can write and must survive
+	start := lowerBound(op.Key)
+	if bytes.Compare(start, end) >= 0 {
+		return nil
+	}
+	return batch.DeleteRange(start, end, pebble.NoSync)
+}
+
+// openRange reports whether the batch stages a DeleteRange without an end.
+func (p *pebbleBatch) openRange() bool {
+	for _, op := range p.ops {
+		if op.Type == zerokv.DeleteRangeOp && op.End == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeEnd returns the key right after the last one an open range starting at start can cover,
+// nil when there is none.
+func (p *pebbleBatch) rangeEnd(start []byte) ([]byte, error) {
+	it, err := p.db.db.NewIter(&pebble.IterOptions{LowerBound: lowerBound(start)})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	defer it.Close()
+	last := p.maxKey
+	if it.Last() && bytes.Compare(it.Key(), last) > 0 {
+		last = it.Key()
+	}
+	if err := it.Error(); err != nil {
+		return nil, convertError(err)
+	}
+	if last == nil || bytes.Compare(last, start) < 0 {
+		return nil, nil
+	}
+	// appending 0x00 gives the smallest key greater than last
+	end := make([]byte, len(last)+1)
+	copy(end, last)
+	return end, nil
+}
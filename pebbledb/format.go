@@ -0,0 +1,180 @@
+package pebbledb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/rawbytedev/zerokv"
+)
+
+/*
+Stores written before value headers existed hold bare values, which the header
+decoding would misread. The empty key, which callers can never write, records the
+storage format:
+
+	formatMagic | formatVersion | flags | resume key
+
+flags has formatExpiring set once an expiring value was written, the expiry sweep
+only runs on such stores. A resume key means a legacy migration is under way and
+done up to that key.
+
+NewPebbleDB stamps an empty store, refuses an unmarked one with ErrIncompatibleFormat
+and, with Config.MigrateLegacy, prefixes every legacy value with a plain header.
+Migration commits in chunks that carry their progress, an interrupted one resumes
+on the next open. Scans start at minKey so the marker never shows up.
+*/
+
+const (
+	formatMagic   = "zerokv-pebble"
+	formatVersion = 1
+	// formatExpiring flags stores that hold expiring values.
+	formatExpiring byte = 1 << 0
+	// migrateBatchSize bounds the bytes of legacy values rewritten per commit.
+	migrateBatchSize = 4 << 20
+)
+
+var (
+	formatKey = []byte{}
+	// minKey is the smallest key callers can write.
+	minKey = []byte{0x00}
+)
+
+// lowerBound returns key as an iterator lower bound that skips formatKey.
+func lowerBound(key []byte) []byte {
+	if len(key) == 0 {
+		return minKey
+	}
+	return key
+}
+
+// storeFormat is the decoded format marker.
+type storeFormat struct {
+	flags  byte
+	resume []byte
+}
+
+func (f storeFormat) encode() []byte {
+	raw := append([]byte(formatMagic), formatVersion, f.flags)
+	return append(raw, f.resume...)
+}
+
+// readFormat returns the format marker of the store, found is false when it has none.
+func (p *pebbleDB) readFormat() (f storeFormat, found bool, err error) {
+	val, closer, err := p.db.Get(formatKey)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return f, false, nil
+	}
+	if err != nil {
+		return f, false, convertError(err)
+	}
+	defer closer.Close()
+	head := len(formatMagic) + 1
+	if len(val) < head+1 || string(val[:len(formatMagic)]) != formatMagic || val[len(formatMagic)] != formatVersion {
+		return f, true, fmt.Errorf("%w: unknown pebble format marker %q", zerokv.ErrIncompatibleFormat, val)
+	}
+	f.flags = val[head]
+	if len(val) > head+1 {
+		f.resume = bytes.Clone(val[head+1:])
+	}
+	return f, true, nil
+}
+
+// checkFormat makes sure the store uses the current format, stamping or migrating it as allowed.
+func (p *pebbleDB) checkFormat(migrate, readOnly bool) (storeFormat, error) {
+	f, found, err := p.readFormat()
+	if err != nil || (found && f.resume == nil) {
+		return f, err
+	}
+	if !found {
+		empty, err := p.empty()
+		if err != nil {
+			return f, err
+		}
+		if empty {
+			if readOnly {
+				return f, nil
+			}
+			return f, convertError(p.db.Set(formatKey, f.encode(), pebble.Sync))
+		}
+	}
+	if !migrate || readOnly {
+		return f, fmt.Errorf("%w: pebble store predates value headers, open it with Config.MigrateLegacy", zerokv.ErrIncompatibleFormat)
+	}
+	return storeFormat{}, p.migrateLegacy(f.resume)
+}
+
+// empty reports whether the store holds no key besides formatKey.
+func (p *pebbleDB) empty() (bool, error) {
+	it, err := p.db.NewIter(&pebble.IterOptions{LowerBound: minKey})
+	if err != nil {
+		return false, convertError(err)
+	}
+	defer it.Close()
+	found := it.First()
+	return !found, convertError(it.Error())
+}
+
+// migrateLegacy prefixes every bare value after resume with a plain header.
+func (p *pebbleDB) migrateLegacy(resume []byte) error {
+	opts := &pebble.IterOptions{LowerBound: minKey}
+	if resume != nil {
+		// appending 0x00 gives the smallest key after resume
+		opts.LowerBound = append(bytes.Clone(resume), 0)
+	}
+	// the iterator reads a consistent view, rewritten values are never seen again
+	it, err := p.db.NewIter(opts)
+	if err != nil {
+		return convertError(err)
+	}
+	defer it.Close()
+	batch := p.db.NewBatch()
+	defer func() { batch.Close() }()
+	for valid := it.First(); valid; valid = it.Next() {
+		raw, err := it.ValueAndErr()
+		if err != nil {
+			return convertError(err)
+		}
+		if err := batch.Set(it.Key(), encodeValue(raw, time.Time{}), nil); err != nil {
+			return err
+		}
+		if batch.Len() < migrateBatchSize {
+			continue
+		}
+		if err := batch.Set(formatKey, storeFormat{resume: it.Key()}.encode(), nil); err != nil {
+			return err
+		}
+		if err := batch.Commit(pebble.Sync); err != nil {
+			return convertError(err)
+		}
+		batch.Close()
+		batch = p.db.NewBatch()
+	}
+	if err := it.Error(); err != nil {
+		return convertError(err)
+	}
+	if err := batch.Set(formatKey, storeFormat{}.encode(), nil); err != nil {
+		return err
+	}
+	return convertError(batch.Commit(pebble.Sync))
+}
+
+// markExpiring records that the store holds expiring values and starts the sweep, once.
+func (p *pebbleDB) markExpiring() error {
+	if p.expiring.Load() {
+		return nil
+	}
+	p.sweepMu.Lock()
+	defer p.sweepMu.Unlock()
+	if p.expiring.Load() {
+		return nil
+	}
+	if err := p.db.Set(formatKey, storeFormat{flags: formatExpiring}.encode(), pebble.Sync); err != nil {
+		return convertError(err)
+	}
+	p.expiring.Store(true)
+	p.startSweep()
+	return nil
+}
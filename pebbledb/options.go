@@ -1,16 +1,27 @@
 package pebbledb
 
 import (
+	"time"
+
 	"github.com/cockroachdb/pebble"
+	"github.com/rawbytedev/zerokv"
 )
 
 // specific Pebbledb options
 type Config struct {
 	Dir           string
 	PebbleConfigs *pebble.Options
-	
+	// Retry controls how Update retries on conflicts, nil uses zerokv.DefaultRetryPolicy
+	Retry *zerokv.RetryPolicy
+	// Clock is used for TTL expiry, nil uses time.Now
+	Clock func() time.Time
+	// SweepInterval is how often expired keys are deleted, 0 uses DefaultSweepInterval and a negative value disables the sweep.
+	// The sweep only runs on stores that hold expiring values
+	SweepInterval time.Duration
+	// MigrateLegacy converts a store written before value headers existed instead of refusing to open it
+	MigrateLegacy bool
 }
 
 func DefaultOptions(Dir string) *Config {
-	return &Config{Dir, nil}
+	return &Config{Dir: Dir}
 }
@@ -0,0 +1,66 @@
+package pebbledb
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"slices"
+
+	"github.com/rawbytedev/zerokv"
+)
+
+/*
+Partitions follow sstable boundaries: the largest key of every table in range is a
+candidate split, weighted with EstimateDiskUsage over the span since the previous
+candidate. Keys only in the memtable are not estimated, a store without tables in
+range is split evenly between its first and last key.
+*/
+
+// ParallelScan scans prefix in partitions of about the same disk usage, with workers goroutines.
+func (p *pebbleDB) ParallelScan(ctx context.Context, prefix []byte, workers int, fn zerokv.ScanFunc) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	splits, err := p.splits(ctx, prefix, workers*zerokv.PartitionsPerWorker)
+	if err != nil {
+		return err
+	}
+	return zerokv.ScanPartitions(ctx, p, prefix, splits, workers, fn)
+}
+
+// splits returns up to n-1 split keys under prefix.
+func (p *pebbleDB) splits(ctx context.Context, prefix []byte, n int) ([][]byte, error) {
+	lower, upper := zerokv.ScanOptions{Prefix: prefix}.Bounds()
+	levels, err := p.db.SSTables()
+	if err != nil {
+		return nil, convertError(err)
+	}
+	var keys [][]byte
+	for _, tables := range levels {
+		for _, t := range tables {
+			key := t.Largest.UserKey
+			if bytes.Compare(key, lower) > 0 && (upper == nil || bytes.Compare(key, upper) < 0) {
+				keys = append(keys, copyBytes(key))
+			}
+		}
+	}
+	slices.SortFunc(keys, bytes.Compare)
+	keys = slices.CompactFunc(keys, bytes.Equal)
+	points := make([]zerokv.SplitPoint, 0, len(keys))
+	prev := lower
+	for _, key := range keys {
+		size, err := p.db.EstimateDiskUsage(prev, key)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		points = append(points, zerokv.SplitPoint{Key: key, Size: size})
+		prev = key
+	}
+	if splits := zerokv.SizedSplits(points, n); len(splits) > 0 {
+		return splits, nil
+	}
+	return zerokv.EvenSplits(ctx, p, prefix, n)
+}
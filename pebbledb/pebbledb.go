@@ -1,21 +1,57 @@
 package pebbledb
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/rawbytedev/zerokv"
 )
 
 type pebbleDB struct {
-	db *pebble.DB
+	db    *pebble.DB
+	retry zerokv.RetryPolicy
+	txnMu sync.Mutex // serialises transaction validation and commit
+	// stripes guard keys read and rewritten by the atomic helpers, a key maps to one stripe through seed
+	stripes [64]sync.Mutex
+	seed    maphash.Seed
+	// writeMu is held shared by every write and exclusively by the expiry sweep, transaction
+	// commits and the final check-and-write of the atomic helpers
+	writeMu sync.RWMutex
+	clock   func() time.Time
+	// expiring is set once the store holds expiring values, the sweep only runs then
+	expiring      atomic.Bool
+	sweepMu       sync.Mutex // guards starting the sweep against Close
+	sweepInterval time.Duration
+	sweeping      bool
+	stop          chan struct{}
+	sweeper       sync.WaitGroup
+	closed        atomic.Bool
 }
 type pebbleBatch struct {
+	db    *pebbleDB
 	batch *pebble.Batch
+	// maxKey is the largest key put through the batch, an open DeleteRange must reach past it
+	maxKey []byte
+	// ops records the staged operations for Encode
+	ops  []zerokv.Operations
+	size int
+	// done is set once the batch is committed or discarded, Reset clears it
+	done bool
 }
 type pebbleIterator struct {
 	Iterator *pebble.Iterator
+	db       *pebbleDB
+	value    []byte // payload of the current entry, header stripped
+	ctx      context.Context
+	opts     zerokv.ScanOptions
+	count    int
 	started  bool
 	valid    bool
 	err      []error
@@ -33,42 +69,226 @@ func NewPebbleDB(cfg Config) (zerokv.Core, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &pebbleDB{db: db}, nil
+	retry := zerokv.DefaultRetryPolicy
+	if cfg.Retry != nil {
+		retry = *cfg.Retry
+	}
+	clock := time.Now
+	if cfg.Clock != nil {
+		clock = cfg.Clock
+	}
+	p := &pebbleDB{db: db, retry: retry, seed: maphash.MakeSeed(), clock: clock, stop: make(chan struct{})}
+	p.sweepInterval = cfg.SweepInterval
+	if p.sweepInterval == 0 {
+		p.sweepInterval = DefaultSweepInterval
+	}
+	format, err := p.checkFormat(cfg.MigrateLegacy, opts.ReadOnly)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	if format.flags&formatExpiring != 0 {
+		p.expiring.Store(true)
+		p.sweepMu.Lock()
+		p.startSweep()
+		p.sweepMu.Unlock()
+	}
+	return p, nil
 }
 
 // --- Basic CRUD operations ---
 
 // Put inserts or updates a key-value pair in the database.
-func (p *pebbleDB) Put(ctx context.Context, key []byte, data []byte) error {
-	if err := ctx.Err(); err != nil {
+func (p *pebbleDB) Put(ctx context.Context, key []byte, data []byte, opts ...zerokv.WriteOptions) error {
+	if err := p.check(ctx); err != nil {
 		return err
 	}
-	return p.db.Set(key, data, pebble.Sync)
+	return p.set(key, encodeValue(data, time.Time{}), writeOptions(opts))
+}
+
+// set stores an encoded value, shared writeMu keeps the sweep from deleting it halfway.
+func (p *pebbleDB) set(key []byte, raw []byte, wo *pebble.WriteOptions) error {
+	if len(key) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	p.writeMu.RLock()
+	defer p.writeMu.RUnlock()
+	return convertError(p.db.Set(key, raw, wo))
+}
+
+// writeOptions maps zerokv write options to pebble's.
+func writeOptions(opts []zerokv.WriteOptions) *pebble.WriteOptions {
+	if zerokv.WriteOptionsOf(opts).Sync {
+		return pebble.Sync
+	}
+	return pebble.NoSync
 }
 
 // Get retrieves the value for a given key. Returns an error if not found.
 func (p *pebbleDB) Get(ctx context.Context, key []byte) ([]byte, error) {
-	if err := ctx.Err(); err != nil {
+	if err := p.check(ctx); err != nil {
 		return nil, err
 	}
-	val, closer, err := p.db.Get(key)
+	return p.getValue(p.db, key)
+}
+
+// getValue reads a caller-owned copy of the live value for key from r.
+func (p *pebbleDB) getValue(r pebble.Reader, key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, zerokv.ErrEmptyKey
+	}
+	val, closer, err := r.Get(key)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	defer closer.Close()
+	payload, ok, err := p.live(val)
 	if err != nil {
 		return nil, err
 	}
+	if !ok {
+		return nil, zerokv.ErrNotFound
+	}
+	// val is only valid until closer.Close()
+	return copyBytes(payload), nil
+}
+
+// GetFunc calls fn with the value for key, val is only valid inside fn.
+func (p *pebbleDB) GetFunc(ctx context.Context, key []byte, fn func(val []byte) error) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	val, closer, err := p.db.Get(key)
+	if err != nil {
+		return convertError(err)
+	}
 	defer closer.Close()
-	return val, nil
+	payload, ok, err := p.live(val)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return zerokv.ErrNotFound
+	}
+	return fn(payload)
+}
+
+// GetMany retrieves every key from a single snapshot.
+func (p *pebbleDB) GetMany(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+	values := make([][]byte, len(keys))
+	errs := make([]error, len(keys))
+	if err := p.check(ctx); err != nil {
+		return values, fillErrors(errs, err)
+	}
+	snap := p.db.NewSnapshot()
+	defer snap.Close()
+	for i, key := range keys {
+		values[i], errs[i] = p.getValue(snap, key)
+	}
+	return values, errs
+}
+
+// PutMany writes all pairs through one batch.
+func (p *pebbleDB) PutMany(ctx context.Context, keys [][]byte, values [][]byte) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
+	if len(keys) != len(values) {
+		return zerokv.ErrLengthMismatch
+	}
+	batch := p.Batch()
+	defer batch.Discard()
+	for i := range keys {
+		if err := batch.Put(keys[i], values[i]); err != nil {
+			return err
+		}
+	}
+	return batch.Commit(ctx)
+}
+
+// fillErrors reports err for every key of a multi-key read that failed as a whole.
+func fillErrors(errs []error, err error) []error {
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Has reports whether key exists and has not expired.
+func (p *pebbleDB) Has(ctx context.Context, key []byte) (bool, error) {
+	found, err := p.HasMany(ctx, [][]byte{key})
+	if err != nil {
+		return false, err
+	}
+	return found[0], nil
+}
+
+// HasMany reports whether each key exists using a single iterator, so one consistent view.
+func (p *pebbleDB) HasMany(ctx context.Context, keys [][]byte) ([]bool, error) {
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+	it, err := p.db.NewIter(&pebble.IterOptions{LowerBound: minKey})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	defer it.Close()
+	found := make([]bool, len(keys))
+	for i, key := range keys {
+		if len(key) == 0 {
+			return nil, zerokv.ErrEmptyKey
+		}
+		if !it.SeekGE(key) || !bytes.Equal(it.Key(), key) {
+			continue
+		}
+		raw, err := header(it)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		if _, found[i], err = p.live(raw); err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, convertError(err)
+	}
+	return found, nil
 }
 
 // Del deletes a key-value pair from the database.
-func (p *pebbleDB) Delete(ctx context.Context, key []byte) error {
-	if err := ctx.Err(); err != nil {
+func (p *pebbleDB) Delete(ctx context.Context, key []byte, opts ...zerokv.WriteOptions) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	p.writeMu.RLock()
+	defer p.writeMu.RUnlock()
+	return convertError(p.db.Delete(key, writeOptions(opts)))
+}
+
+// Sync syncs the WAL, making every write acknowledged so far durable.
+func (p *pebbleDB) Sync(ctx context.Context) error {
+	if err := p.check(ctx); err != nil {
 		return err
 	}
-	return p.db.Delete(key, pebble.Sync)
+	// an empty synced log record flushes the WAL behind every earlier write
+	return convertError(p.db.LogData(nil, pebble.Sync))
 }
 
 // Close closes the database and releases all resources.
 func (p *pebbleDB) Close() error {
+	if !p.closed.CompareAndSwap(false, true) {
+		return zerokv.ErrClosed
+	}
+	p.sweepMu.Lock()
+	close(p.stop)
+	p.sweepMu.Unlock()
+	p.sweeper.Wait()
 	var errs []error
 	if err := p.db.Close(); err != nil {
 		errs = append(errs, err)
@@ -79,69 +299,450 @@ func (p *pebbleDB) Close() error {
 	return errors.Join(errs...)
 }
 
+// check reports context cancellation or use of a closed database.
+func (p *pebbleDB) check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if p.closed.Load() {
+		return zerokv.ErrClosed
+	}
+	return nil
+}
+
+// convertError translates pebble errors into zerokv sentinels.
+func convertError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, pebble.ErrNotFound):
+		return zerokv.ErrNotFound
+	case errors.Is(err, pebble.ErrClosed):
+		return zerokv.Wrap(zerokv.ErrClosed, err)
+	}
+	return err
+}
+
+// copyBytes detaches a slice from pebble-owned memory.
+func copyBytes(b []byte) []byte {
+	data := make([]byte, len(b))
+	copy(data, b)
+	return data
+}
+
 // -- Batch operations
 
+// Batch returns an indexed batch, so Get and Scan can read its pending writes.
 func (p *pebbleDB) Batch() zerokv.Batch {
-	return &pebbleBatch{batch: p.db.NewBatch()}
+	return &pebbleBatch{db: p, batch: p.db.NewIndexedBatch()}
+}
+
+// ApplyEncodedBatch commits the operations of an encoded batch through one pebble batch.
+func (p *pebbleDB) ApplyEncodedBatch(ctx context.Context, data []byte) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
+	ops, err := zerokv.DecodeOperations(data)
+	if err != nil {
+		return err
+	}
+	batch := p.Batch()
+	defer batch.Discard()
+	if err := zerokv.ApplyOperations(batch, ops, p.clock()); err != nil {
+		return err
+	}
+	return batch.Commit(ctx)
 }
 
 func (p *pebbleBatch) Put(key []byte, data []byte) error {
-	return p.batch.Set(key, data, pebble.NoSync)
+	return p.set(key, data, time.Time{})
+}
+
+// set stages data under key with its value header.
+func (p *pebbleBatch) set(key []byte, data []byte, expiresAt time.Time) error {
+	if p.done {
+		return zerokv.ErrBatchCommitted
+	}
+	if len(key) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	if bytes.Compare(key, p.maxKey) > 0 {
+		p.maxKey = append(p.maxKey[:0], key...)
+	}
+	op := zerokv.Operations{Key: copyBytes(key), Value: copyBytes(data), ExpiresAt: expiresAt, Type: zerokv.PutOp}
+	if err := p.stage(p.batch, op); err != nil {
+		return err
+	}
+	p.record(op)
+	return nil
 }
 
 // BatchDel adds a delete operation to the current batch.
 func (p *pebbleBatch) Delete(key []byte) error {
-	return p.batch.Delete(key, pebble.NoSync)
+	if p.done {
+		return zerokv.ErrBatchCommitted
+	}
+	if len(key) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	op := zerokv.Operations{Key: copyBytes(key), Type: zerokv.DeleteOp}
+	if err := p.stage(p.batch, op); err != nil {
+		return err
+	}
+	p.record(op)
+	return nil
+}
+
+// stage writes a recorded operation to batch.
+func (p *pebbleBatch) stage(batch *pebble.Batch, op zerokv.Operations) error {
+	switch op.Type {
+	case zerokv.PutOp:
+		return batch.Set(op.Key, encodeValue(op.Value, op.ExpiresAt), pebble.NoSync)
+	case zerokv.DeleteOp:
+		return batch.Delete(op.Key, pebble.NoSync)
+	case zerokv.DeleteRangeOp:
+		return p.stageRange(batch, op)
+	}
+	return fmt.Errorf("unknown batch operation %d", op.Type)
+}
+
+// record keeps op for Encode and accounts for its size.
+func (p *pebbleBatch) record(op zerokv.Operations) {
+	p.ops = append(p.ops, op)
+	p.size += len(op.Key) + len(op.Value) + len(op.End)
 }
 
 // flushBatch flushes any pending batch operations.
-func (p *pebbleBatch) Commit(ctx context.Context) error {
-	return p.batch.Commit(pebble.Sync)
+func (p *pebbleBatch) Commit(ctx context.Context, opts ...zerokv.WriteOptions) error {
+	if p.done {
+		return zerokv.ErrBatchCommitted
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if p.expiring() {
+		if err := p.db.markExpiring(); err != nil {
+			return err
+		}
+	}
+	if p.openRange() {
+		return p.commitOpen(ctx, opts)
+	}
+	p.db.writeMu.RLock()
+	defer p.db.writeMu.RUnlock()
+	// an expiry sweep may have held writeMu for a while
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := p.batch.Commit(writeOptions(opts))
+	p.release()
+	return convertError(err)
+}
+
+// commitOpen commits a batch staging open ranges, their ends are found again while
+// writeMu is held exclusively so no key written before Commit can land past them.
+func (p *pebbleBatch) commitOpen(ctx context.Context, opts []zerokv.WriteOptions) error {
+	p.db.writeMu.Lock()
+	defer p.db.writeMu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if p.db.closed.Load() {
+		return zerokv.ErrClosed
+	}
+	// a tombstone keeps its place among the other operations, the batch is staged again in order
+	batch := p.db.db.NewBatch()
+	defer batch.Close()
+	for _, op := range p.ops {
+		if err := p.stage(batch, op); err != nil {
+			return err
+		}
+	}
+	err := batch.Commit(writeOptions(opts))
+	p.release()
+	return convertError(err)
+}
+
+// expiring reports whether the batch stages an expiring value.
+func (p *pebbleBatch) expiring() bool {
+	for _, op := range p.ops {
+		if !op.ExpiresAt.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// Discard drops the staged operations and releases the batch.
+func (p *pebbleBatch) Discard() {
+	p.release()
+}
+
+// Reset drops the staged operations so the batch can be reused, even after Commit.
+func (p *pebbleBatch) Reset() {
+	p.release()
+	p.batch = p.db.db.NewIndexedBatch()
+	p.maxKey = p.maxKey[:0]
+	p.ops = nil
+	p.size, p.done = 0, false
+}
+
+// release closes the pebble batch once, it must not be touched afterwards.
+func (p *pebbleBatch) release() {
+	if !p.done {
+		p.done = true
+		_ = p.batch.Close()
+	}
+}
+
+// Get retrieves the value for key, operations staged in the batch included.
+func (p *pebbleBatch) Get(key []byte) ([]byte, error) {
+	if p.done {
+		return nil, zerokv.ErrBatchCommitted
+	}
+	if err := p.db.check(context.Background()); err != nil {
+		return nil, err
+	}
+	return p.db.getValue(p.batch, key)
+}
+
+// Scan iterates over keys starting with prefix, operations staged in the batch included.
+func (p *pebbleBatch) Scan(prefix []byte) zerokv.Iterator {
+	if p.done {
+		return zerokv.NewErrIterator(zerokv.ErrBatchCommitted)
+	}
+	if err := p.db.check(context.Background()); err != nil {
+		return zerokv.NewErrIterator(err)
+	}
+	// pebble batch iterators only see the writes staged before they were opened
+	return p.db.newIterator(context.Background(), p.batch, zerokv.ScanOptions{Prefix: prefix})
+}
+
+// Encode returns the staged operations in the zerokv encoded batch format.
+func (p *pebbleBatch) Encode() []byte {
+	return zerokv.EncodeOperations(p.ops)
+}
+
+// Savepoint marks the operations staged so far.
+func (p *pebbleBatch) Savepoint() int {
+	return len(p.ops)
+}
+
+// RollbackTo rebuilds the pebble batch from the operations staged before sp.
+func (p *pebbleBatch) RollbackTo(sp int) error {
+	if p.done {
+		return zerokv.ErrBatchCommitted
+	}
+	if sp < 0 || sp > len(p.ops) {
+		return zerokv.ErrInvalidSavepoint
+	}
+	if sp == len(p.ops) {
+		return nil
+	}
+	// pebble cannot truncate a batch, the kept operations are staged again on a fresh one,
+	// exactly as recorded so expiries are not turned back into TTLs
+	kept := p.ops[:sp]
+	p.Reset()
+	for _, op := range kept {
+		if op.Type == zerokv.PutOp && bytes.Compare(op.Key, p.maxKey) > 0 {
+			p.maxKey = append(p.maxKey[:0], op.Key...)
+		}
+		if err := p.stage(p.batch, op); err != nil {
+			return err
+		}
+		p.record(op)
+	}
+	return nil
+}
+
+// Len returns the number of operations staged.
+func (p *pebbleBatch) Len() int {
+	return len(p.ops)
+}
+
+// ByteSize returns the total size of the keys and values staged.
+func (p *pebbleBatch) ByteSize() int {
+	return p.size
 }
 
 // -- Iterator operations
 
 func (p *pebbleDB) Scan(prefix []byte) zerokv.Iterator {
-	upbound := make([]byte, len(prefix))
-	copy(upbound, prefix)
-	upbound[len(upbound)-1]++
+	return p.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext iterates over keys starting with prefix, the iterator stops once ctx is done.
+func (p *pebbleDB) ScanContext(ctx context.Context, prefix []byte) zerokv.Iterator {
+	return p.ScanRange(ctx, zerokv.ScanOptions{Prefix: prefix})
+}
+
+// ScanKeys iterates over keys starting with prefix, values are never copied out of pebble.
+func (p *pebbleDB) ScanKeys(ctx context.Context, prefix []byte) zerokv.Iterator {
+	return p.ScanRange(ctx, zerokv.ScanOptions{Prefix: prefix, KeysOnly: true})
+}
+
+// ForEach calls fn for every key under prefix, key and val are only valid inside fn.
+func (p *pebbleDB) ForEach(ctx context.Context, prefix []byte, fn func(key, val []byte) error) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
 	it, err := p.db.NewIter(&pebble.IterOptions{
-		LowerBound: prefix,
-		UpperBound: upbound,
+		LowerBound: lowerBound(prefix),
+		UpperBound: zerokv.PrefixUpperBound(prefix),
 	})
 	if err != nil {
-		return nil
+		return convertError(err)
+	}
+	defer it.Close()
+	for valid := it.First(); valid; valid = it.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		raw, err := it.ValueAndErr()
+		if err != nil {
+			return convertError(err)
+		}
+		val, ok, err := p.live(raw)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := fn(it.Key(), val); err != nil {
+			return err
+		}
+	}
+	return convertError(it.Error())
+}
+
+// ScanRange iterates over keys in [opts.Start, opts.End).
+func (p *pebbleDB) ScanRange(ctx context.Context, opts zerokv.ScanOptions) zerokv.Iterator {
+	if err := p.check(ctx); err != nil {
+		return zerokv.NewErrIterator(err)
+	}
+	return p.newIterator(ctx, p.db, opts)
+}
+
+// newIterator opens an iterator over r, bounds are enforced by pebble itself.
+func (p *pebbleDB) newIterator(ctx context.Context, r pebble.Reader, opts zerokv.ScanOptions) zerokv.Iterator {
+	opts.Start, opts.End = opts.Bounds()
+	it, err := r.NewIter(&pebble.IterOptions{
+		LowerBound: lowerBound(opts.Start),
+		UpperBound: opts.End,
+	})
+	if err != nil {
+		return zerokv.NewErrIterator(convertError(err))
 	}
-	return &pebbleIterator{Iterator: it, valid: false, started: false}
+	return &pebbleIterator{Iterator: it, db: p, ctx: ctx, opts: opts}
 }
 
 func (it *pebbleIterator) Next() bool {
-	// this comes from how iterators works in pebble
 	if !it.started {
-		it.valid = it.Iterator.First()
-		it.started = true
-	} else {
-		it.valid = it.Iterator.Next()
+		return it.First()
+	}
+	if !it.ready() {
+		return false
+	}
+	// this comes from how iterators works in pebble
+	return it.settle(it.step())
+}
+
+// First moves back to the first entry of the scan.
+func (it *pebbleIterator) First() bool {
+	it.started, it.count = true, 0
+	if !it.ready() {
+		return false
+	}
+	if it.opts.Reverse {
+		return it.settle(it.Iterator.Last())
+	}
+	return it.settle(it.Iterator.First())
+}
+
+// Seek moves to the first key >= key, or the last key <= key for reverse scans.
+func (it *pebbleIterator) Seek(key []byte) bool {
+	it.started, it.count = true, 0
+	if !it.ready() {
+		return false
+	}
+	if it.opts.Reverse {
+		// the smallest key after key is key+0x00, anything below it is <= key
+		return it.settle(it.Iterator.SeekLT(append(key[:len(key):len(key)], 0)))
+	}
+	return it.settle(it.Iterator.SeekGE(key))
+}
+
+// ready checks the context and the scan limit before moving the iterator.
+func (it *pebbleIterator) ready() bool {
+	if err := it.ctx.Err(); err != nil {
+		it.err = append(it.err, err)
+		it.valid = false
+		return false
+	}
+	if it.opts.Limit > 0 && it.count >= it.opts.Limit {
+		it.valid = false
+		return false
+	}
+	return true
+}
+
+// settle records whether the iterator landed on an entry within the scan, skipping expired entries.
+func (it *pebbleIterator) settle(valid bool) bool {
+	for ; valid; valid = it.step() {
+		raw, err := it.raw()
+		if err != nil {
+			it.err = append(it.err, convertError(err))
+			valid = false
+			break
+		}
+		payload, ok, err := it.db.live(raw)
+		if err != nil {
+			it.err = append(it.err, err)
+			valid = false
+			break
+		}
+		if ok {
+			it.value = payload
+			break
+		}
+	}
+	it.valid = valid
+	if it.valid {
+		it.count++
 	}
 	return it.valid
 }
 
+// raw returns the stored value of the current entry, keys-only scans only read its header.
+func (it *pebbleIterator) raw() ([]byte, error) {
+	if it.opts.KeysOnly {
+		return header(it.Iterator)
+	}
+	return it.Iterator.ValueAndErr()
+}
+
+// step moves one entry in the scan direction.
+func (it *pebbleIterator) step() bool {
+	if it.opts.Reverse {
+		return it.Iterator.Prev()
+	}
+	return it.Iterator.Next()
+}
+
 func (it *pebbleIterator) Key() []byte {
 	if !it.valid {
 		return nil
 	}
-	return it.Iterator.Key() // safer, doesn't make changes to key
+	// pebble reuses the key buffer on every move
+	return copyBytes(it.Iterator.Key())
 }
 func (it *pebbleIterator) Value() []byte {
-	if !it.valid {
+	if !it.valid || it.opts.KeysOnly {
 		return nil
 	}
-	data, err := it.Iterator.ValueAndErr()
-	if err != nil {
-		it.err = append(it.err, err)
-		return nil
-	}
-	return data
+	// it.value points into pebble memory that moves with the iterator
+	return copyBytes(it.value)
 }
 func (it *pebbleIterator) Release() {
 	it.valid = false
@@ -156,40 +757,21 @@ func (it *pebbleIterator) Error() error {
 
 // --- specials methods to use with an instance of badgerdb for some other operations
 func NewIterator(p *pebbleDB) zerokv.Iterator {
-	it, err := p.db.NewIter(&pebble.IterOptions{})
-
-	if err != nil {
-		return nil
-	}
-	return &pebbleIterator{Iterator: it, valid: false, started: false}
+	return p.ScanRange(context.Background(), zerokv.ScanOptions{})
 }
 
 func NewPrefixIterator(p *pebbleDB, prefix []byte) zerokv.Iterator {
-	upbound := make([]byte, len(prefix))
-	copy(upbound, prefix)
-	upbound[len(upbound)-1]++
-	it, err := p.db.NewIter(&pebble.IterOptions{
-		LowerBound: prefix,
-		UpperBound: upbound,
-	})
-	if err != nil {
-		return nil
-	}
-	return &pebbleIterator{Iterator: it, valid: false, started: false}
+	return p.ScanRange(context.Background(), zerokv.ScanOptions{Prefix: prefix})
 }
 
 /*
-Due to how pebble works reverse Iterators have to be built using a different struct mainly because
-we need to make use
-it.Prev()
-it.Last()
-in Next()
+Due to how pebble works reverse iterators start from it.Last()
+and move with it.Prev() in Next(), this is handled by pebbleIterator when opts.Reverse is set
 */
 
-func NewReverseIterator(p *pebbleDB, prefix []byte) zerokv.Iterator {
-	return nil
+func NewReverseIterator(p *pebbleDB) zerokv.Iterator {
+	return p.ScanRange(context.Background(), zerokv.ScanOptions{Reverse: true})
 }
-func NewReversePrefixIterator(prefix []byte) zerokv.Iterator {
-	// Placeholder for Reverse Prefix Iterator implementation
-	return nil
+func NewReversePrefixIterator(p *pebbleDB, prefix []byte) zerokv.Iterator {
+	return p.ScanRange(context.Background(), zerokv.ScanOptions{Prefix: prefix, Reverse: true})
 }
@@ -2,38 +2,136 @@ package pebbledb_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/pebble"
+	"github.com/rawbytedev/zerokv"
 	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/rawbytedev/zerokv/pebbledb"
 	"github.com/stretchr/testify/require"
 )
 
-// TestPebbleBatchOperations tests batch Put and Get operations.
-func TestPebbleBatchOperations(t *testing.T) {
+// TestPebbleTxnPhantomConflict tests that keys inserted into a scanned prefix conflict, pebble validates whole scanned ranges.
+func TestPebbleTxnPhantomConflict(t *testing.T) {
 	db := helpers.SetupDB(t, "pebbledb")
-	batch := db.Batch()
-	keys := make([][]byte, 5)
-	values := make([][]byte, 5)
-	for i := 0; i < 5; i++ {
-		keys[i] = helpers.RandomBytes(16)
-		values[i] = helpers.RandomBytes(32)
-		err := batch.Put(keys[i], values[i])
-		require.NoError(t, err, "Error adding Put operation to batch")
-	}
-	err := batch.Commit(t.Context())
-	require.NoError(t, err, "Error committing batch operations")
-	for i := 0; i < 5; i++ {
-		retrievedValue, err := db.Get(t.Context(), keys[i])
-		require.NoError(t, err, "Error getting value after batch commit")
-		require.Equal(t, values[i], retrievedValue, "Retrieved value does not match expected after batch commit")
-	}
-	// Attempting to use the batch after commit should result in panic this behavior is specific to PebbleDB
-	// This should fail because the batch has already been committed
-	require.Panics(t, func() {
-		batch.Put(keys[0], values[1])
+	defer db.Close()
+	require.NoError(t, db.Put(t.Context(), []byte("row_1"), []byte("a")))
+	attempts := 0
+	err := db.Update(t.Context(), func(tx zerokv.Txn) error {
+		attempts++
+		it := tx.Scan([]byte("row_"))
+		count := 0
+		for it.Next() {
+			count++
+		}
+		it.Release()
+		if attempts == 1 {
+			require.NoError(t, db.Put(t.Context(), []byte("row_2"), []byte("b")))
+		}
+		return tx.Put([]byte("rows"), []byte{byte(count)})
 	})
-	// This should also fail because the batch has already been committed
-	require.Panics(t, func() {
-		batch.Commit(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+	value, err := db.Get(t.Context(), []byte("rows"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, value)
+}
+
+// TestPebbleTTLSweep tests that the background sweep physically deletes expired keys.
+func TestPebbleTTLSweep(t *testing.T) {
+	clock := helpers.NewFakeClock()
+	db, err := pebbledb.NewPebbleDB(pebbledb.Config{
+		Dir:           t.TempDir(),
+		Clock:         clock.Now,
+		SweepInterval: 10 * time.Millisecond,
 	})
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.PutWithTTL(t.Context(), []byte("expiring"), []byte("a"), time.Minute))
+	require.NoError(t, db.Put(t.Context(), []byte("plain"), []byte("b")))
+	clock.Advance(2 * time.Minute)
+	// rewinding the clock would revive the key unless the sweep already deleted it
+	require.Eventually(t, func() bool {
+		clock.Advance(-2 * time.Minute)
+		defer clock.Advance(2 * time.Minute)
+		_, err := db.Get(t.Context(), []byte("expiring"))
+		return zerokv.IsNotFound(err)
+	}, 5*time.Second, 20*time.Millisecond)
+	val, err := db.Get(t.Context(), []byte("plain"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), val)
+}
+
+// TestPebbleTTLSweepReopen tests that a reopened store holding expiring keys sweeps them without a new TTL write.
+func TestPebbleTTLSweepReopen(t *testing.T) {
+	clock := helpers.NewFakeClock()
+	dir := t.TempDir()
+	cfg := pebbledb.Config{Dir: dir, Clock: clock.Now, SweepInterval: 10 * time.Millisecond}
+	db, err := pebbledb.NewPebbleDB(cfg)
+	require.NoError(t, err)
+	require.NoError(t, db.PutWithTTL(t.Context(), []byte("expiring"), []byte("a"), time.Minute))
+	require.NoError(t, db.Close())
+	db, err = pebbledb.NewPebbleDB(cfg)
+	require.NoError(t, err)
 	defer db.Close()
+	clock.Advance(2 * time.Minute)
+	require.Eventually(t, func() bool {
+		clock.Advance(-2 * time.Minute)
+		defer clock.Advance(2 * time.Minute)
+		_, err := db.Get(t.Context(), []byte("expiring"))
+		return zerokv.IsNotFound(err)
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+// TestPebbleLegacyFormat tests that stores written without value headers are refused unless migrated.
+func TestPebbleLegacyFormat(t *testing.T) {
+	dir := t.TempDir()
+	raw, err := pebble.Open(dir, &pebble.Options{})
+	require.NoError(t, err)
+	require.NoError(t, raw.Set([]byte("k"), []byte("hello"), pebble.Sync))
+	require.NoError(t, raw.Set([]byte("z"), []byte{0, 1, 2}, pebble.Sync))
+	require.NoError(t, raw.Close())
+	_, err = pebbledb.NewPebbleDB(pebbledb.Config{Dir: dir})
+	require.ErrorIs(t, err, zerokv.ErrIncompatibleFormat)
+	db, err := pebbledb.NewPebbleDB(pebbledb.Config{Dir: dir, MigrateLegacy: true})
+	require.NoError(t, err)
+	val, err := db.Get(t.Context(), []byte("k"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), val)
+	val, err = db.Get(t.Context(), []byte("z"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 1, 2}, val)
+	require.NoError(t, db.Close())
+	// migrated stores open as usual and are not migrated twice
+	db, err = pebbledb.NewPebbleDB(pebbledb.Config{Dir: dir, MigrateLegacy: true})
+	require.NoError(t, err)
+	defer db.Close()
+	val, err = db.Get(t.Context(), []byte("z"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 1, 2}, val)
+}
+
+// TestPebbleFormatMarkerHidden tests that the format marker stays out of reach of every read and delete.
+func TestPebbleFormatMarkerHidden(t *testing.T) {
+	dir := t.TempDir()
+	db, err := pebbledb.NewPebbleDB(pebbledb.Config{Dir: dir})
+	require.NoError(t, err)
+	require.NoError(t, db.Put(t.Context(), []byte("key"), []byte("value")))
+	it := db.ScanRange(t.Context(), zerokv.ScanOptions{})
+	require.True(t, it.Next())
+	require.Equal(t, []byte("key"), it.Key())
+	require.True(t, it.Seek(nil))
+	require.Equal(t, []byte("key"), it.Key())
+	it.Release()
+	_, err = db.Get(t.Context(), []byte{})
+	require.ErrorIs(t, err, zerokv.ErrEmptyKey)
+	_, err = db.Has(t.Context(), []byte{})
+	require.ErrorIs(t, err, zerokv.ErrEmptyKey)
+	require.NoError(t, db.Put(t.Context(), []byte("zzz"), []byte("value")))
+	require.NoError(t, db.DeleteRange(t.Context(), nil, []byte("zz")))
+	require.NoError(t, db.Close())
+	// an unmarked store with keys would be refused, so the marker survived the open range delete
+	db, err = pebbledb.NewPebbleDB(pebbledb.Config{Dir: dir})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
 }
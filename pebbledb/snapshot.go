@@ -0,0 +1,42 @@
+package pebbledb
+
+import (
+	"context"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/rawbytedev/zerokv"
+)
+
+type pebbleSnapshot struct {
+	db   *pebbleDB
+	snap *pebble.Snapshot
+}
+
+// Snapshot captures a pebble.Snapshot of the current state.
+func (p *pebbleDB) Snapshot() (zerokv.Snapshot, error) {
+	if p.closed.Load() {
+		return nil, zerokv.ErrClosed
+	}
+	return &pebbleSnapshot{db: p, snap: p.db.NewSnapshot()}, nil
+}
+
+// Get retrieves the value for key as of the snapshot.
+func (s *pebbleSnapshot) Get(ctx context.Context, key []byte) ([]byte, error) {
+	if err := s.db.check(ctx); err != nil {
+		return nil, err
+	}
+	return s.db.getValue(s.snap, key)
+}
+
+// Scan iterates over keys starting with prefix as of the snapshot.
+func (s *pebbleSnapshot) Scan(prefix []byte) zerokv.Iterator {
+	if err := s.db.check(context.Background()); err != nil {
+		return zerokv.NewErrIterator(err)
+	}
+	return s.db.newIterator(context.Background(), s.snap, zerokv.ScanOptions{Prefix: prefix})
+}
+
+// Release closes the underlying pebble.Snapshot.
+func (s *pebbleSnapshot) Release() {
+	s.snap.Close()
+}
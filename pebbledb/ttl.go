@@ -0,0 +1,202 @@
+package pebbledb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/rawbytedev/zerokv"
+)
+
+/*
+Pebble has no TTL, every stored value starts with a one byte header:
+	plainValue    | payload
+	expiringValue | expiry (8 bytes, big-endian unix nanoseconds) | payload
+Reads filter expired values lazily and, once the store holds expiring values, a
+background sweep deletes them for good.
+*/
+
+const (
+	plainValue    byte = 0x00
+	expiringValue byte = 0x01
+	expiryLen          = 8
+	// DefaultSweepInterval is used when Config.SweepInterval is zero.
+	DefaultSweepInterval = time.Minute
+	// sweepBatchSize bounds how many keys a sweep deletes while holding writeMu.
+	sweepBatchSize = 64
+)
+
+// encodeValue prefixes data with its header, a zero expiresAt never expires.
+func encodeValue(data []byte, expiresAt time.Time) []byte {
+	if expiresAt.IsZero() {
+		raw := make([]byte, 1+len(data))
+		raw[0] = plainValue
+		copy(raw[1:], data)
+		return raw
+	}
+	raw := make([]byte, 1+expiryLen+len(data))
+	raw[0] = expiringValue
+	binary.BigEndian.PutUint64(raw[1:], uint64(expiresAt.UnixNano()))
+	copy(raw[1+expiryLen:], data)
+	return raw
+}
+
+// decodeValue splits a stored value into its payload and expiry in unix nanoseconds, 0 when none.
+func decodeValue(raw []byte) ([]byte, int64, error) {
+	switch {
+	case len(raw) >= 1 && raw[0] == plainValue:
+		return raw[1:], 0, nil
+	case len(raw) >= 1+expiryLen && raw[0] == expiringValue:
+		return raw[1+expiryLen:], int64(binary.BigEndian.Uint64(raw[1:])), nil
+	}
+	return nil, 0, fmt.Errorf("%w: invalid pebble value header", zerokv.ErrCorruptValue)
+}
+
+// live decodes raw and reports whether it has not expired yet.
+func (p *pebbleDB) live(raw []byte) ([]byte, bool, error) {
+	payload, expiresAt, err := decodeValue(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	if expiresAt != 0 && expiresAt <= p.clock().UnixNano() {
+		return nil, false, nil
+	}
+	return payload, true, nil
+}
+
+// header returns the current value of it for decodeValue without going through ValueAndErr.
+/*
+	Values stored inline are read in place, only values pebble keeps out of line
+	in value blocks have to be fetched to reach their header.
+*/
+func header(it *pebble.Iterator) ([]byte, error) {
+	if lv := it.LazyValue(); lv.Fetcher == nil {
+		return lv.ValueOrHandle, nil
+	}
+	return it.ValueAndErr()
+}
+
+// expiry returns the absolute expiry for ttl, zero when ttl is not positive.
+func (p *pebbleDB) expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return p.clock().Add(ttl)
+}
+
+// PutWithTTL inserts or updates a key-value pair that expires after ttl.
+func (p *pebbleDB) PutWithTTL(ctx context.Context, key []byte, data []byte, ttl time.Duration) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
+	expiresAt := p.expiry(ttl)
+	if !expiresAt.IsZero() {
+		if err := p.markExpiring(); err != nil {
+			return err
+		}
+	}
+	return p.set(key, encodeValue(data, expiresAt), pebble.Sync)
+}
+
+// PutWithTTL inserts or updates a key-value pair in the batch that expires after ttl.
+func (p *pebbleBatch) PutWithTTL(key []byte, data []byte, ttl time.Duration) error {
+	return p.set(key, data, p.db.expiry(ttl))
+}
+
+// startSweep starts the expiry sweep unless it runs already, is disabled or the store is closed.
+// sweepMu must be held.
+func (p *pebbleDB) startSweep() {
+	if p.sweeping || p.sweepInterval < 0 || p.closed.Load() {
+		return
+	}
+	p.sweeping = true
+	p.sweeper.Add(1)
+	go p.sweepLoop(p.sweepInterval)
+}
+
+// sweepLoop deletes expired keys every interval until Close.
+func (p *pebbleDB) sweepLoop(interval time.Duration) {
+	defer p.sweeper.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			// failures are retried on the next tick, expired keys stay hidden meanwhile
+			_ = p.sweep()
+		}
+	}
+}
+
+// sweep walks the whole keyspace, reading value headers only, and deletes every expired key.
+func (p *pebbleDB) sweep() error {
+	it, err := p.db.NewIter(&pebble.IterOptions{LowerBound: minKey})
+	if err != nil {
+		return convertError(err)
+	}
+	defer it.Close()
+	var expired [][]byte
+	for valid := it.First(); valid; valid = it.Next() {
+		raw, err := header(it)
+		if err != nil {
+			return convertError(err)
+		}
+		if _, ok, err := p.live(raw); err == nil && !ok {
+			expired = append(expired, copyBytes(it.Key()))
+		}
+		if len(expired) == sweepBatchSize {
+			if err := p.deleteExpired(expired); err != nil {
+				return err
+			}
+			expired = expired[:0]
+		}
+	}
+	if err := it.Error(); err != nil {
+		return convertError(err)
+	}
+	return p.deleteExpired(expired)
+}
+
+// deleteExpired deletes the sorted keys that are still expired.
+/*
+	writeMu is held exclusively so no write can revive a key between the check and
+	the delete. The keys are few and sorted, the check is a header read per key
+	through one iterator, so writers are only held up briefly.
+*/
+func (p *pebbleDB) deleteExpired(keys [][]byte) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	it, err := p.db.NewIter(&pebble.IterOptions{LowerBound: keys[0]})
+	if err != nil {
+		return convertError(err)
+	}
+	defer it.Close()
+	batch := p.db.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		if !it.SeekGE(key) || !bytes.Equal(it.Key(), key) {
+			continue
+		}
+		raw, err := header(it)
+		if err != nil {
+			return convertError(err)
+		}
+		if _, ok, err := p.live(raw); err == nil && !ok {
+			if err := batch.Delete(key, nil); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return convertError(err)
+	}
+	return convertError(batch.Commit(pebble.NoSync))
+}
@@ -0,0 +1,189 @@
+package pebbledb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/rawbytedev/zerokv"
+)
+
+/*
+Pebble has no transactions, they are emulated with optimistic concurrency:
+writes are staged in an indexed batch so the transaction reads its own writes,
+every key read and every prefix scanned is recorded, and on commit the recorded
+reads are compared between a snapshot taken when the transaction started and the
+current state. Any difference means a concurrent writer got there first and the
+transaction fails with zerokv.ErrConflict.
+*/
+
+type pebbleTxn struct {
+	db       *pebbleDB
+	snap     *pebble.Snapshot
+	batch    *pebble.Batch // nil for read-only transactions
+	reads    [][]byte
+	scans    []zerokv.ScanOptions
+	writes   map[string]struct{}
+	readOnly bool
+}
+
+// Update runs fn in an emulated optimistic transaction, retrying on zerokv.ErrConflict.
+func (p *pebbleDB) Update(ctx context.Context, fn func(tx zerokv.Txn) error) error {
+	return p.retry.Do(ctx, func() error {
+		if err := p.check(ctx); err != nil {
+			return err
+		}
+		tx := &pebbleTxn{
+			db:     p,
+			snap:   p.db.NewSnapshot(),
+			batch:  p.db.NewIndexedBatch(),
+			writes: make(map[string]struct{}),
+		}
+		defer tx.discard()
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.commit()
+	})
+}
+
+// View runs fn against a snapshot, writes fail with zerokv.ErrReadOnly.
+func (p *pebbleDB) View(ctx context.Context, fn func(tx zerokv.Txn) error) error {
+	if err := p.check(ctx); err != nil {
+		return err
+	}
+	tx := &pebbleTxn{db: p, snap: p.db.NewSnapshot(), readOnly: true}
+	defer tx.discard()
+	return fn(tx)
+}
+
+// reader returns the view reads go through, the indexed batch merges staged writes over the database.
+func (t *pebbleTxn) reader() pebble.Reader {
+	if t.readOnly {
+		return t.snap
+	}
+	return t.batch
+}
+
+// Get retrieves the value for key, staged writes included.
+func (t *pebbleTxn) Get(key []byte) ([]byte, error) {
+	if _, ok := t.writes[string(key)]; !ok && !t.readOnly {
+		t.reads = append(t.reads, copyBytes(key))
+	}
+	return t.db.getValue(t.reader(), key)
+}
+
+// Put stages an insert or update in the indexed batch.
+func (t *pebbleTxn) Put(key []byte, data []byte) error {
+	if t.readOnly {
+		return zerokv.ErrReadOnly
+	}
+	if len(key) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	t.writes[string(key)] = struct{}{}
+	return t.batch.Set(key, encodeValue(data, time.Time{}), nil)
+}
+
+// Delete stages a delete in the indexed batch.
+func (t *pebbleTxn) Delete(key []byte) error {
+	if t.readOnly {
+		return zerokv.ErrReadOnly
+	}
+	if len(key) == 0 {
+		return zerokv.ErrEmptyKey
+	}
+	t.writes[string(key)] = struct{}{}
+	return t.batch.Delete(key, nil)
+}
+
+// Scan iterates over keys starting with prefix, staged writes included.
+func (t *pebbleTxn) Scan(prefix []byte) zerokv.Iterator {
+	opts := zerokv.ScanOptions{Prefix: copyBytes(prefix)}
+	if !t.readOnly {
+		t.scans = append(t.scans, opts)
+	}
+	return t.db.newIterator(context.Background(), t.reader(), opts)
+}
+
+// commit validates the read set and applies the batch while holding txnMu and writeMu
+// exclusively, so no other write can interleave.
+func (t *pebbleTxn) commit() error {
+	t.db.txnMu.Lock()
+	defer t.db.txnMu.Unlock()
+	// plain writes and the atomic helpers hold writeMu too, one landing between
+	// validate and Commit would otherwise be overwritten without a conflict
+	t.db.writeMu.Lock()
+	defer t.db.writeMu.Unlock()
+	if t.db.closed.Load() {
+		return zerokv.ErrClosed
+	}
+	if err := t.validate(); err != nil {
+		return err
+	}
+	if t.batch.Empty() {
+		return nil
+	}
+	return convertError(t.batch.Commit(pebble.Sync))
+}
+
+// validate checks that nothing the transaction read has changed since it started.
+func (t *pebbleTxn) validate() error {
+	for _, key := range t.reads {
+		before, errBefore := t.db.getValue(t.snap, key)
+		after, errAfter := t.db.getValue(t.db.db, key)
+		if err := firstUnexpected(errBefore, errAfter); err != nil {
+			return err
+		}
+		if zerokv.IsNotFound(errBefore) != zerokv.IsNotFound(errAfter) || !bytes.Equal(before, after) {
+			return zerokv.ErrConflict
+		}
+	}
+	for _, opts := range t.scans {
+		if err := t.validateScan(opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateScan walks a scanned range in the snapshot and the database side by side.
+func (t *pebbleTxn) validateScan(opts zerokv.ScanOptions) error {
+	before := t.db.newIterator(context.Background(), t.snap, opts)
+	defer before.Release()
+	after := t.db.newIterator(context.Background(), t.db.db, opts)
+	defer after.Release()
+	for {
+		okBefore, okAfter := before.Next(), after.Next()
+		if okBefore != okAfter {
+			return zerokv.ErrConflict
+		}
+		if !okBefore {
+			break
+		}
+		if !bytes.Equal(before.Key(), after.Key()) || !bytes.Equal(before.Value(), after.Value()) {
+			return zerokv.ErrConflict
+		}
+	}
+	return errors.Join(before.Error(), after.Error())
+}
+
+// firstUnexpected returns the first error that is neither nil nor zerokv.ErrNotFound.
+func firstUnexpected(errs ...error) error {
+	for _, err := range errs {
+		if err != nil && !zerokv.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// discard releases the snapshot and the batch.
+func (t *pebbleTxn) discard() {
+	t.snap.Close()
+	if t.batch != nil {
+		t.batch.Close()
+	}
+}
@@ -0,0 +1,54 @@
+package zerokv
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how Update retries transactions that fail with a retryable error.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, values below 1 mean a single attempt.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it doubles after every conflict.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used by backends that are not given a RetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 10,
+	Backoff:     time.Millisecond,
+	MaxBackoff:  100 * time.Millisecond,
+}
+
+// Do runs fn until it succeeds, fails with an error that is not retryable,
+// runs out of attempts or ctx is done. The last error from fn is returned.
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !IsRetryable(err) || attempt >= p.MaxAttempts {
+			return err
+		}
+		if backoff > 0 {
+			// jitter keeps conflicting writers from retrying in lockstep
+			delay := backoff/2 + rand.N(backoff/2+1)
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+			backoff *= 2
+			if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+				backoff = p.MaxBackoff
+			}
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+}
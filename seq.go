@@ -0,0 +1,58 @@
+package zerokv
+
+import (
+	"context"
+	"iter"
+)
+
+// Range-over-func adapters for Core scans.
+/*
+	Every adapter returns the sequence with a companion error func reporting how the
+	last iteration ended, check it once the loop is over:
+
+		entries, errf := zerokv.All(ctx, db, []byte("user_"))
+		for key, val := range entries {
+			...
+		}
+		if err := errf(); err != nil {
+			return err
+		}
+
+	Each loop opens its own iterator and releases it when the loop ends, including on
+	break, return or panic. Keys and values are copies owned by the loop body.
+*/
+
+// All iterates over the keys starting with prefix and their values.
+func All(ctx context.Context, core Core, prefix []byte) (iter.Seq2[[]byte, []byte], func() error) {
+	return Range(ctx, core, ScanOptions{Prefix: prefix})
+}
+
+// Keys iterates over the keys starting with prefix, values are never loaded.
+func Keys(ctx context.Context, core Core, prefix []byte) (iter.Seq[[]byte], func() error) {
+	entries, errf := Range(ctx, core, ScanOptions{Prefix: prefix, KeysOnly: true})
+	keys := func(yield func([]byte) bool) {
+		for key := range entries {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+	return keys, errf
+}
+
+// Range iterates over the entries within the bounds described by opts.
+func Range(ctx context.Context, core Core, opts ScanOptions) (iter.Seq2[[]byte, []byte], func() error) {
+	var err error
+	entries := func(yield func(key, val []byte) bool) {
+		it := core.ScanRange(ctx, opts)
+		defer it.Release()
+		err = nil
+		for it.Next() {
+			if !yield(it.Key(), it.Value()) {
+				break
+			}
+		}
+		err = it.Error()
+	}
+	return entries, func() error { return err }
+}
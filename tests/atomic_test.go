@@ -0,0 +1,239 @@
+package tests
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvAtomic(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestCompareAndSwap",
+			fn: func(t *testing.T, name string) {
+				testCompareAndSwap(t, name)
+			}}, {
+			name: "TestPutIfAbsentDeleteIfEqual",
+			fn: func(t *testing.T, name string) {
+				testPutIfAbsentDeleteIfEqual(t, name)
+			}}, {
+			name: "TestUpdateFunc",
+			fn: func(t *testing.T, name string) {
+				testUpdateFunc(t, name)
+			}}, {
+			name: "TestUpdateFuncWritesStore",
+			fn: func(t *testing.T, name string) {
+				testUpdateFuncWritesStore(t, name)
+			}}, {
+			name: "TestAtomicStress",
+			fn: func(t *testing.T, name string) {
+				testAtomicStress(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+func testCompareAndSwap(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	key := []byte("leader")
+	// nil old only matches a missing key
+	ok, err := db.CompareAndSwap(t.Context(), key, nil, []byte("node-1"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = db.CompareAndSwap(t.Context(), key, nil, []byte("node-2"))
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = db.CompareAndSwap(t.Context(), key, []byte("node-3"), []byte("node-2"))
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = db.CompareAndSwap(t.Context(), key, []byte("node-1"), []byte("node-2"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	value, err := db.Get(t.Context(), key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("node-2"), value)
+	_, err = db.CompareAndSwap(t.Context(), nil, nil, []byte("x"))
+	require.ErrorIs(t, err, zerokv.ErrEmptyKey)
+}
+
+func testPutIfAbsentDeleteIfEqual(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	key := []byte("lock")
+	ok, err := db.PutIfAbsent(t.Context(), key, []byte("owner-a"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = db.PutIfAbsent(t.Context(), key, []byte("owner-b"))
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = db.DeleteIfEqual(t.Context(), key, []byte("owner-b"))
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = db.DeleteIfEqual(t.Context(), key, []byte("owner-a"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = db.DeleteIfEqual(t.Context(), key, []byte("owner-a"))
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = db.PutIfAbsent(t.Context(), key, []byte("owner-b"))
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func testUpdateFunc(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	key := []byte("list")
+	for _, item := range []string{"a", "b", "c"} {
+		err := db.UpdateFunc(t.Context(), key, func(old []byte) ([]byte, error) {
+			return append(old, item...), nil
+		})
+		require.NoError(t, err)
+	}
+	value, err := db.Get(t.Context(), key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("abc"), value)
+	// returning nil deletes the key
+	err = db.UpdateFunc(t.Context(), key, func(old []byte) ([]byte, error) {
+		require.Equal(t, []byte("abc"), old)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	_, err = db.Get(t.Context(), key)
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	errAbort := fmt.Errorf("abort")
+	err = db.UpdateFunc(t.Context(), key, func(old []byte) ([]byte, error) {
+		require.Nil(t, old)
+		return []byte("never stored"), errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+	_, err = db.Get(t.Context(), key)
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+}
+
+// testAtomicStress mixes every atomic helper and Update on the same keys from many goroutines.
+func testUpdateFuncWritesStore(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	key := []byte("doc")
+	require.NoError(t, db.Put(t.Context(), key, []byte("a")))
+	require.NoError(t, db.Put(t.Context(), []byte("tmp"), []byte("x")))
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- db.UpdateFunc(t.Context(), key, func(old []byte) ([]byte, error) {
+			calls++
+			// fn may write to the store, a write to key itself makes fn run again
+			if err := db.Put(t.Context(), []byte("audit"), old); err != nil {
+				return nil, err
+			}
+			if err := db.Delete(t.Context(), []byte("tmp")); err != nil {
+				return nil, err
+			}
+			if calls == 1 {
+				if err := db.Put(t.Context(), key, []byte("b")); err != nil {
+					return nil, err
+				}
+			}
+			return append(old, '!'), nil
+		})
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("UpdateFunc blocked on a write from its callback")
+	}
+	require.Equal(t, 2, calls)
+	val, err := db.Get(t.Context(), key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("b!"), val)
+	val, err = db.Get(t.Context(), []byte("audit"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), val)
+	_, err = db.Get(t.Context(), []byte("tmp"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+}
+
+func testAtomicStress(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	counter := []byte("counter")
+	const workers, rounds = 8, 25
+	var wg sync.WaitGroup
+	var winners atomic.Int32
+	errs := make(chan error, workers*rounds*3+workers)
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := db.PutIfAbsent(t.Context(), []byte("once"), []byte{byte(w)})
+			errs <- err
+			if ok {
+				winners.Add(1)
+			}
+			for range rounds {
+				// compare-and-swap loop
+				for {
+					old, err := db.Get(t.Context(), counter)
+					if zerokv.IsNotFound(err) {
+						old, err = nil, nil
+					}
+					if err != nil {
+						errs <- err
+						break
+					}
+					ok, err := db.CompareAndSwap(t.Context(), counter, old, increment(old))
+					if err != nil || ok {
+						errs <- err
+						break
+					}
+				}
+				errs <- db.UpdateFunc(t.Context(), counter, func(old []byte) ([]byte, error) {
+					return increment(old), nil
+				})
+				errs <- db.Update(t.Context(), func(tx zerokv.Txn) error {
+					old, err := tx.Get(counter)
+					if err != nil && !zerokv.IsNotFound(err) {
+						return err
+					}
+					return tx.Put(counter, increment(old))
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), winners.Load())
+	value, err := db.Get(t.Context(), counter)
+	require.NoError(t, err)
+	require.Equal(t, uint64(workers*rounds*3), binary.BigEndian.Uint64(value))
+}
+
+// increment adds one to a big-endian uint64 counter, nil counts as zero.
+func increment(old []byte) []byte {
+	var n uint64
+	if old != nil {
+		n = binary.BigEndian.Uint64(old)
+	}
+	return binary.BigEndian.AppendUint64(nil, n+1)
+}
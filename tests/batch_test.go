@@ -0,0 +1,284 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvBatch(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestBatchOperations",
+			fn: func(t *testing.T, name string) {
+				testBatchOperations(t, name)
+			}}, {
+			name: "TestBatchAfterCommit",
+			fn: func(t *testing.T, name string) {
+				testBatchAfterCommit(t, name)
+			}}, {
+			name: "TestBatchDiscard",
+			fn: func(t *testing.T, name string) {
+				testBatchDiscard(t, name)
+			}}, {
+			name: "TestBatchReset",
+			fn: func(t *testing.T, name string) {
+				testBatchReset(t, name)
+			}}, {
+			name: "TestBatchLenByteSize",
+			fn: func(t *testing.T, name string) {
+				testBatchLenByteSize(t, name)
+			}}, {
+			name: "TestBatchGet",
+			fn: func(t *testing.T, name string) {
+				testBatchGet(t, name)
+			}}, {
+			name: "TestBatchScan",
+			fn: func(t *testing.T, name string) {
+				testBatchScan(t, name)
+			}}, {
+			name: "TestBatchSavepoint",
+			fn: func(t *testing.T, name string) {
+				testBatchSavepoint(t, name)
+			}}, {
+			name: "TestBatchSavepointExpiry",
+			fn: func(t *testing.T, name string) {
+				testBatchSavepointExpiry(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+// testBatchOperations tests batch Put and Delete are applied on Commit.
+func testBatchOperations(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	require.NoError(t, db.Put(t.Context(), []byte("stale"), []byte("v")))
+	batch := db.Batch()
+	defer batch.Discard()
+	keys := make([][]byte, 5)
+	values := make([][]byte, 5)
+	for i := range 5 {
+		keys[i] = helpers.RandomBytes(16)
+		values[i] = helpers.RandomBytes(32)
+		require.NoError(t, batch.Put(keys[i], values[i]), "Error adding Put operation to batch")
+	}
+	require.NoError(t, batch.Delete([]byte("stale")))
+	require.NoError(t, batch.Commit(t.Context()), "Error committing batch operations")
+	for i := range 5 {
+		retrievedValue, err := db.Get(t.Context(), keys[i])
+		require.NoError(t, err, "Error getting value after batch commit")
+		require.Equal(t, values[i], retrievedValue, "Retrieved value does not match expected after batch commit")
+	}
+	_, err := db.Get(t.Context(), []byte("stale"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+}
+
+// testBatchAfterCommit tests that every backend rejects a committed batch the same way.
+func testBatchAfterCommit(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	batch := db.Batch()
+	require.NoError(t, batch.Put([]byte("key"), []byte("v1")))
+	require.NoError(t, batch.Commit(t.Context()))
+	require.ErrorIs(t, batch.Put([]byte("key"), []byte("v2")), zerokv.ErrBatchCommitted)
+	require.ErrorIs(t, batch.PutWithTTL([]byte("key"), []byte("v2"), ttl), zerokv.ErrBatchCommitted)
+	require.ErrorIs(t, batch.Delete([]byte("key")), zerokv.ErrBatchCommitted)
+	require.ErrorIs(t, batch.DeletePrefix([]byte("k")), zerokv.ErrBatchCommitted)
+	require.ErrorIs(t, batch.Commit(t.Context()), zerokv.ErrBatchCommitted)
+	// Discard after Commit is a no-op
+	batch.Discard()
+	val, err := db.Get(t.Context(), []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("v1"), val)
+}
+
+func testBatchDiscard(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	batch := db.Batch()
+	require.NoError(t, batch.Put([]byte("key"), []byte("v")))
+	batch.Discard()
+	batch.Discard()
+	require.ErrorIs(t, batch.Put([]byte("key"), []byte("v")), zerokv.ErrBatchCommitted)
+	require.ErrorIs(t, batch.Commit(t.Context()), zerokv.ErrBatchCommitted)
+	_, err := db.Get(t.Context(), []byte("key"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+}
+
+func testBatchReset(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	batch := db.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.Put([]byte("dropped"), []byte("v")))
+	batch.Reset()
+	require.Zero(t, batch.Len())
+	require.NoError(t, batch.Put([]byte("first"), []byte("v")))
+	require.NoError(t, batch.Commit(t.Context()))
+	// a committed batch is reusable after Reset
+	batch.Reset()
+	require.NoError(t, batch.Put([]byte("second"), []byte("v")))
+	require.NoError(t, batch.Commit(t.Context()))
+	require.Equal(t, [][]byte{[]byte("first"), []byte("second")}, collectKeys(t, db.Scan(nil)))
+}
+
+func testBatchLenByteSize(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	batch := db.Batch()
+	defer batch.Discard()
+	require.Zero(t, batch.Len())
+	require.Zero(t, batch.ByteSize())
+	require.NoError(t, batch.Put([]byte("key_1"), []byte("value")))
+	require.NoError(t, batch.PutWithTTL([]byte("key_2"), []byte("value"), ttl))
+	require.NoError(t, batch.Delete([]byte("key_3")))
+	// rejected operations are not counted
+	require.ErrorIs(t, batch.Put(nil, []byte("value")), zerokv.ErrEmptyKey)
+	require.Equal(t, 3, batch.Len())
+	require.Equal(t, 25, batch.ByteSize())
+	require.NoError(t, batch.DeleteRange([]byte("a"), []byte("b")))
+	require.Equal(t, 4, batch.Len())
+	require.Equal(t, 27, batch.ByteSize())
+}
+
+// testBatchGet tests that a batch reads its own pending writes over the database.
+func testBatchGet(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	require.NoError(t, db.Put(t.Context(), []byte("stored"), []byte("db")))
+	require.NoError(t, db.Put(t.Context(), []byte("removed"), []byte("db")))
+	batch := db.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.Put([]byte("stored"), []byte("batch")))
+	require.NoError(t, batch.Put([]byte("staged"), []byte("new")))
+	require.NoError(t, batch.Delete([]byte("removed")))
+	val, err := batch.Get([]byte("stored"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("batch"), val)
+	val, err = batch.Get([]byte("staged"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), val)
+	_, err = batch.Get([]byte("removed"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	_, err = batch.Get([]byte("missing"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	// the database is untouched until Commit
+	val, err = db.Get(t.Context(), []byte("stored"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("db"), val)
+	require.NoError(t, batch.Commit(t.Context()))
+	_, err = batch.Get([]byte("stored"))
+	require.ErrorIs(t, err, zerokv.ErrBatchCommitted)
+}
+
+// testBatchScan tests that batch scans merge staged writes, deletes and range deletes in key order.
+func testBatchScan(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillOrdered(t, db, 6)
+	batch := db.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.Put([]byte("key_01"), []byte("updated")))
+	require.NoError(t, batch.Put([]byte("key_02a"), []byte("inserted")))
+	require.NoError(t, batch.Delete([]byte("key_03")))
+	require.NoError(t, batch.DeleteRange([]byte("key_04"), []byte("key_06")))
+	require.NoError(t, batch.Put([]byte("key_07"), []byte("appended")))
+	require.NoError(t, batch.Put([]byte("other"), []byte("outside")))
+	it := batch.Scan([]byte("key_"))
+	var keys, values []string
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+		values = append(values, string(it.Value()))
+	}
+	require.NoError(t, it.Error())
+	require.Equal(t, []string{"key_00", "key_01", "key_02", "key_02a", "key_07"}, keys)
+	require.Equal(t, []string{"value_00", "updated", "value_02", "inserted", "appended"}, values)
+	require.True(t, it.Seek([]byte("key_02")))
+	require.Equal(t, []byte("key_02"), it.Key())
+	require.True(t, it.Next())
+	require.Equal(t, []byte("key_02a"), it.Key())
+	require.True(t, it.Seek([]byte("key_03")))
+	require.Equal(t, []byte("key_07"), it.Key())
+	// writes staged after Scan are not visible to the open iterator
+	require.NoError(t, batch.Put([]byte("key_08"), []byte("late")))
+	require.False(t, it.Next())
+	it.Release()
+	require.Len(t, collectKeys(t, batch.Scan([]byte("key_"))), 6)
+	require.NoError(t, batch.Commit(t.Context()))
+	require.Len(t, collectKeys(t, db.Scan([]byte("key_"))), 6)
+}
+
+// testBatchSavepoint tests nested savepoints, rolling back to the inner one first and then the outer one.
+func testBatchSavepoint(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillOrdered(t, db, 3)
+	batch := db.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.Put([]byte("group_1"), []byte("ok")))
+	outer := batch.Savepoint()
+	require.NoError(t, batch.Put([]byte("group_2"), []byte("ok")))
+	require.NoError(t, batch.Delete([]byte("key_00")))
+	inner := batch.Savepoint()
+	require.NoError(t, batch.Put([]byte("group_2"), []byte("bad")))
+	require.NoError(t, batch.DeletePrefix([]byte("key_")))
+	require.NoError(t, batch.RollbackTo(inner))
+	require.Equal(t, inner, batch.Len())
+	val, err := batch.Get([]byte("group_2"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("ok"), val)
+	require.Equal(t, [][]byte{[]byte("key_01"), []byte("key_02")}, collectKeys(t, batch.Scan([]byte("key_"))))
+	require.NoError(t, batch.RollbackTo(outer))
+	require.Equal(t, 1, batch.Len())
+	// the inner savepoint is gone with the operations it covered
+	require.ErrorIs(t, batch.RollbackTo(inner), zerokv.ErrInvalidSavepoint)
+	require.ErrorIs(t, batch.RollbackTo(-1), zerokv.ErrInvalidSavepoint)
+	require.NoError(t, batch.PutWithTTL([]byte("group_3"), []byte("ok"), ttl))
+	require.NoError(t, batch.Commit(t.Context()))
+	require.ErrorIs(t, batch.RollbackTo(outer), zerokv.ErrBatchCommitted)
+	require.Equal(t, [][]byte{
+		[]byte("group_1"),
+		[]byte("group_3"),
+		[]byte("key_00"),
+		[]byte("key_01"),
+		[]byte("key_02"),
+	}, collectKeys(t, db.Scan(nil)))
+}
+
+func testBatchSavepointExpiry(t *testing.T, name string) {
+	clock := helpers.NewFakeClock()
+	db := helpers.SetupDBWithClock(t, name, clock.Now)
+	defer db.Close()
+	batch := db.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.PutWithTTL([]byte("long"), []byte("a"), ttl))
+	require.NoError(t, batch.PutWithTTL([]byte("short"), []byte("b"), ttl/2))
+	kept, err := zerokv.DecodeOperations(batch.Encode())
+	require.NoError(t, err)
+	sp := batch.Savepoint()
+	require.NoError(t, batch.Put([]byte("dropped"), []byte("c")))
+	// a rollback later on leaves the kept operations exactly as they were staged
+	clock.Advance(ttl * 3 / 4)
+	require.NoError(t, batch.RollbackTo(sp))
+	ops, err := zerokv.DecodeOperations(batch.Encode())
+	require.NoError(t, err)
+	require.Equal(t, kept, ops)
+	require.NoError(t, batch.Commit(t.Context()))
+	_, err = db.Get(t.Context(), []byte("short"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	clock.Advance(ttl / 2)
+	_, err = db.Get(t.Context(), []byte("long"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+}
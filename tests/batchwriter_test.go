@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvBatchWriter(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestBatchWriterMaxOps",
+			fn: func(t *testing.T, name string) {
+				testBatchWriterMaxOps(t, name)
+			}}, {
+			name: "TestBatchWriterMaxBytes",
+			fn: func(t *testing.T, name string) {
+				testBatchWriterMaxBytes(t, name)
+			}}, {
+			name: "TestBatchWriterResume",
+			fn: func(t *testing.T, name string) {
+				testBatchWriterResume(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+func testBatchWriterMaxOps(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	var flushes []zerokv.BatchProgress
+	w := zerokv.NewBatchWriter(db, zerokv.BatchWriterOptions{
+		MaxOps:  4,
+		OnFlush: func(p zerokv.BatchProgress) { flushes = append(flushes, p) },
+	})
+	for i := range 10 {
+		require.NoError(t, w.Put(t.Context(), []byte(fmt.Sprintf("key_%02d", i)), []byte("v")))
+	}
+	// two full sub-batches are already committed, the last two keys are still staged
+	require.Len(t, flushes, 2)
+	require.Equal(t, []byte("key_07"), flushes[1].LastKey)
+	require.Len(t, collectKeys(t, db.Scan([]byte("key_"))), 8)
+	require.NoError(t, w.Delete(t.Context(), []byte("key_00")))
+	require.NoError(t, w.Close(t.Context()))
+	progress := w.Progress()
+	require.Equal(t, 3, progress.Batches)
+	require.Equal(t, 11, progress.Ops)
+	require.Equal(t, []byte("key_00"), progress.LastKey)
+	require.Len(t, collectKeys(t, db.Scan([]byte("key_"))), 9)
+}
+
+func testBatchWriterMaxBytes(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	w := zerokv.NewBatchWriter(db, zerokv.BatchWriterOptions{MaxBytes: 1 << 10})
+	value := helpers.RandomBytes(300)
+	for i := range 10 {
+		require.NoError(t, w.Put(t.Context(), []byte(fmt.Sprintf("key_%02d", i)), value))
+	}
+	require.NoError(t, w.Close(t.Context()))
+	progress := w.Progress()
+	// every sub-batch is flushed by the fourth 306 byte put
+	require.Equal(t, 3, progress.Batches)
+	require.Equal(t, 10, progress.Ops)
+	require.Equal(t, 10*306, progress.Bytes)
+	require.Len(t, collectKeys(t, db.Scan([]byte("key_"))), 10)
+}
+
+func testBatchWriterResume(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	ctx, cancel := context.WithCancel(t.Context())
+	w := zerokv.NewBatchWriter(db, zerokv.BatchWriterOptions{MaxOps: 3})
+	for i := range 5 {
+		require.NoError(t, w.Put(ctx, []byte(fmt.Sprintf("key_%02d", i)), []byte("v")))
+	}
+	// the load is interrupted with two keys staged
+	cancel()
+	require.ErrorIs(t, w.Put(ctx, []byte("key_05"), []byte("v")), context.Canceled)
+	require.ErrorIs(t, w.Close(ctx), context.Canceled)
+	resume := w.Progress().LastKey
+	require.Equal(t, []byte("key_02"), resume)
+	require.Len(t, collectKeys(t, db.Scan([]byte("key_"))), 3)
+}
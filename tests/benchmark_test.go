@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+)
+
+var benchDBs = []string{"badgerdb", "pebbledb"}
+
+// fillBench stores n entries with values of size bytes under the "bench_" prefix.
+func fillBench(b *testing.B, db zerokv.Core, n, size int) [][]byte {
+	keys := make([][]byte, n)
+	batch := db.Batch()
+	for i := range n {
+		keys[i] = []byte(fmt.Sprintf("bench_%06d", i))
+		if err := batch.Put(keys[i], helpers.RandomBytes(size)); err != nil {
+			b.Fatalf("Failed to stage key-value pair: %v", err)
+		}
+	}
+	if err := batch.Commit(b.Context()); err != nil {
+		b.Fatalf("Failed to commit batch: %v", err)
+	}
+	return keys
+}
+
+// BenchmarkGet compares the copying Get with the zero-copy GetFunc.
+func BenchmarkGet(b *testing.B) {
+	for _, name := range benchDBs {
+		db := helpers.SetupDB(b, name)
+		keys := fillBench(b, db, 1000, 1024)
+		b.Run(name+"/Get", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				if _, err := db.Get(b.Context(), keys[i%len(keys)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(name+"/GetFunc", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				if err := db.GetFunc(b.Context(), keys[i%len(keys)], func(val []byte) error {
+					return nil
+				}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		db.Close()
+	}
+}
+
+// BenchmarkScan compares the copying Iterator with the zero-copy ForEach.
+func BenchmarkScan(b *testing.B) {
+	for _, name := range benchDBs {
+		db := helpers.SetupDB(b, name)
+		fillBench(b, db, 1000, 1024)
+		b.Run(name+"/Iterator", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				it := db.Scan([]byte("bench_"))
+				for it.Next() {
+					_ = it.Key()
+					_ = it.Value()
+				}
+				it.Release()
+			}
+		})
+		b.Run(name+"/ForEach", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := db.ForEach(b.Context(), []byte("bench_"), func(key, val []byte) error {
+					return nil
+				}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		db.Close()
+	}
+}
+
+// BenchmarkScanKeys compares listing keys with a full scan and a keys-only scan over large values.
+func BenchmarkScanKeys(b *testing.B) {
+	for _, name := range benchDBs {
+		db := helpers.SetupDB(b, name)
+		fillBench(b, db, 1000, 16<<10)
+		b.Run(name+"/Scan", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				it := db.Scan([]byte("bench_"))
+				for it.Next() {
+					_ = it.Key()
+				}
+				it.Release()
+			}
+		})
+		b.Run(name+"/ScanKeys", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				it := db.ScanKeys(b.Context(), []byte("bench_"))
+				for it.Next() {
+					_ = it.Key()
+				}
+				it.Release()
+			}
+		})
+		db.Close()
+	}
+}
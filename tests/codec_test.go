@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// TestZeroKvEncodedBatch tests that a batch encoded on one backend applies identically on every backend.
+func TestZeroKvEncodedBatch(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	for _, from := range dbs {
+		for _, to := range dbs {
+			t.Run(fmt.Sprintf("TestEncodedBatch%sTo%s", from, to), func(t *testing.T) {
+				testEncodedBatch(t, from, to)
+			})
+		}
+	}
+	for _, from := range dbs {
+		for _, to := range dbs {
+			t.Run(fmt.Sprintf("TestEncodedOpenRange%sTo%s", from, to), func(t *testing.T) {
+				testEncodedOpenRange(t, from, to)
+			})
+		}
+	}
+	for _, name := range dbs {
+		t.Run("TestEncodedBatchCorrupt"+name, func(t *testing.T) {
+			testEncodedBatchCorrupt(t, name)
+		})
+	}
+}
+
+func testEncodedBatch(t *testing.T, from, to string) {
+	clock := helpers.NewFakeClock()
+	src := helpers.SetupDBWithClock(t, from, clock.Now)
+	defer src.Close()
+	dst := helpers.SetupDBWithClock(t, to, clock.Now)
+	defer dst.Close()
+	FillOrdered(t, dst, 6)
+	require.NoError(t, dst.Put(t.Context(), []byte("stale"), []byte("v")))
+	batch := src.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.Put([]byte("key_01"), []byte("updated")))
+	require.NoError(t, batch.Put([]byte("empty"), []byte{}))
+	require.NoError(t, batch.PutWithTTL([]byte("session"), []byte("token"), ttl))
+	require.NoError(t, batch.Delete([]byte("stale")))
+	require.NoError(t, batch.DeleteRange([]byte("key_03"), []byte("key_05")))
+	require.NoError(t, batch.DeleteRange([]byte("key_05a"), nil))
+	data := batch.Encode()
+	// applying does not depend on the source batch being committed
+	batch.Discard()
+	require.NoError(t, dst.ApplyEncodedBatch(t.Context(), data))
+	require.Equal(t, [][]byte{
+		[]byte("empty"),
+		[]byte("key_00"),
+		[]byte("key_01"),
+		[]byte("key_02"),
+		[]byte("key_05"),
+	}, collectKeys(t, dst.Scan(nil)))
+	val, err := dst.Get(t.Context(), []byte("key_01"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("updated"), val)
+	// the open range was resolved on dst, so keys put afterwards survive
+	require.NoError(t, dst.Put(t.Context(), []byte("key_09"), []byte("v")))
+	// the expiry travels with the batch
+	clock.Advance(2 * ttl)
+	_, err = dst.Get(t.Context(), []byte("session"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	found, err := dst.Has(t.Context(), []byte("key_09"))
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+// testEncodedOpenRange encodes an open range staged against an empty store, it still applies to the keys of dst.
+func testEncodedOpenRange(t *testing.T, from, to string) {
+	src := helpers.SetupDB(t, from)
+	defer src.Close()
+	dst := helpers.SetupDB(t, to)
+	defer dst.Close()
+	require.NoError(t, dst.Put(t.Context(), []byte("a"), []byte("v")))
+	require.NoError(t, dst.Put(t.Context(), []byte("b"), []byte("v")))
+	batch := src.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.DeleteRange([]byte("b"), nil))
+	require.Equal(t, 1, batch.Len())
+	require.NoError(t, dst.ApplyEncodedBatch(t.Context(), batch.Encode()))
+	require.Equal(t, [][]byte{[]byte("a")}, collectKeys(t, dst.Scan(nil)))
+}
+
+func testEncodedBatchCorrupt(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	batch := db.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.Put([]byte("key"), []byte("value")))
+	data := batch.Encode()
+	flipped := append([]byte{}, data...)
+	flipped[len(flipped)/2] ^= 0xFF
+	require.ErrorIs(t, db.ApplyEncodedBatch(t.Context(), flipped), zerokv.ErrCorruptBatch)
+	require.ErrorIs(t, db.ApplyEncodedBatch(t.Context(), data[:len(data)-1]), zerokv.ErrCorruptBatch)
+	require.ErrorIs(t, db.ApplyEncodedBatch(t.Context(), nil), zerokv.ErrCorruptBatch)
+	// an empty batch round trips
+	empty := db.Batch()
+	defer empty.Discard()
+	require.NoError(t, db.ApplyEncodedBatch(t.Context(), empty.Encode()))
+	_, err := db.Get(t.Context(), []byte("key"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+}
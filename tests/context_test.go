@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvContext(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestScanContextCancel",
+			fn: func(t *testing.T, name string) {
+				testScanContextCancel(t, name)
+			}}, {
+			name: "TestScanContextDeadline",
+			fn: func(t *testing.T, name string) {
+				testScanContextDeadline(t, name)
+			}}, {
+			name: "TestCommitDeadline",
+			fn: func(t *testing.T, name string) {
+				testCommitDeadline(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+func testScanContextCancel(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillOrdered(t, db, 20)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	it := db.ScanContext(ctx, []byte("key_"))
+	defer it.Release()
+	seen := 0
+	for it.Next() {
+		seen++
+		if seen == 5 {
+			cancel()
+		}
+	}
+	require.Equal(t, 5, seen)
+	require.ErrorIs(t, it.Error(), context.Canceled)
+	require.False(t, it.First())
+}
+
+func testScanContextDeadline(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillOrdered(t, db, 20)
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	it := db.ScanContext(ctx, []byte("key_"))
+	defer it.Release()
+	require.True(t, it.Next())
+	<-ctx.Done()
+	require.False(t, it.Next())
+	require.ErrorIs(t, it.Error(), context.DeadlineExceeded)
+	// a scan started after the deadline never yields
+	late := db.ScanContext(ctx, []byte("key_"))
+	defer late.Release()
+	require.False(t, late.Next())
+	require.ErrorIs(t, late.Error(), context.DeadlineExceeded)
+}
+
+func testCommitDeadline(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	batch := db.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.Put([]byte("key"), []byte("v")))
+	ctx, cancel := context.WithTimeout(t.Context(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	require.ErrorIs(t, batch.Commit(ctx), context.DeadlineExceeded)
+	_, err := db.Get(t.Context(), []byte("key"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	// the batch is left intact and commits with a live context
+	require.Equal(t, 1, batch.Len())
+	require.NoError(t, batch.Commit(t.Context()))
+	_, err = db.Get(t.Context(), []byte("key"))
+	require.NoError(t, err)
+}
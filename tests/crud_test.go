@@ -1,9 +1,11 @@
 package tests
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/rawbytedev/zerokv"
 	"github.com/rawbytedev/zerokv/helpers"
 	"github.com/stretchr/testify/require"
 )
@@ -33,6 +35,41 @@ func TestZeroKvImplementation(t *testing.T) {
 			fn: func(t *testing.T, name string) {
 				testClose(t, name)
 			}},
+		{
+			name: "TestGetOwnership",
+			fn: func(t *testing.T, name string) {
+				testGetOwnership(t, name)
+			}},
+		{
+			name: "TestBatchInputOwnership",
+			fn: func(t *testing.T, name string) {
+				testBatchInputOwnership(t, name)
+			}},
+		{
+			name: "TestGetFunc",
+			fn: func(t *testing.T, name string) {
+				testGetFunc(t, name)
+			}},
+		{
+			name: "TestHas",
+			fn: func(t *testing.T, name string) {
+				testHas(t, name)
+			}},
+		{
+			name: "TestGetManyPutMany",
+			fn: func(t *testing.T, name string) {
+				testGetManyPutMany(t, name)
+			}},
+		{
+			name: "TestEmptyKey",
+			fn: func(t *testing.T, name string) {
+				testEmptyKey(t, name)
+			}},
+		{
+			name: "TestUseAfterClose",
+			fn: func(t *testing.T, name string) {
+				testUseAfterClose(t, name)
+			}},
 	}
 
 	for i := range dbs {
@@ -66,7 +103,7 @@ func testGetPutDelete(t *testing.T, name string) {
 		err = db.Delete(t.Context(), keys[i])
 		require.NoError(t, err, "Error deleting key")
 		_, err = db.Get(t.Context(), keys[i])
-		require.Error(t, err, "Expected error retrieving deleted key")
+		require.ErrorIs(t, err, zerokv.ErrNotFound, "Expected not found retrieving deleted key")
 	}
 	defer db.Close()
 }
@@ -76,7 +113,10 @@ func testGetNonExistentKey(t *testing.T, name string) {
 	db := helpers.SetupDB(t, name)
 	nonExistentKey := helpers.RandomBytes(16)
 	_, err := db.Get(t.Context(), nonExistentKey)
-	require.Error(t, err, "Expected error when getting non-existent key")
+	require.ErrorIs(t, err, zerokv.ErrNotFound, "Expected not found when getting non-existent key")
+	require.True(t, zerokv.IsNotFound(err))
+	require.False(t, zerokv.IsRetryable(err))
+	require.True(t, zerokv.IsRetryable(fmt.Errorf("put: %w", zerokv.ErrWritesBlocked)))
 	defer db.Close()
 }
 
@@ -105,3 +145,160 @@ func testClose(t *testing.T, name string) {
 	err := db.Close()
 	require.NoError(t, err, "Error closing PebbleDB")
 }
+
+// testEmptyKey tests that empty keys are rejected the same way by every backend.
+func testEmptyKey(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	err := db.Put(t.Context(), []byte{}, helpers.RandomBytes(8))
+	require.ErrorIs(t, err, zerokv.ErrEmptyKey)
+	err = db.Delete(t.Context(), nil)
+	require.ErrorIs(t, err, zerokv.ErrEmptyKey)
+	err = db.Batch().Put(nil, helpers.RandomBytes(8))
+	require.ErrorIs(t, err, zerokv.ErrEmptyKey)
+}
+
+// testUseAfterClose tests that a closed database reports ErrClosed instead of panicking.
+func testUseAfterClose(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	key := helpers.RandomBytes(16)
+	require.NoError(t, db.Put(t.Context(), key, helpers.RandomBytes(32)))
+	require.NoError(t, db.Close())
+	err := db.Put(t.Context(), key, helpers.RandomBytes(32))
+	require.ErrorIs(t, err, zerokv.ErrClosed)
+	_, err = db.Get(t.Context(), key)
+	require.ErrorIs(t, err, zerokv.ErrClosed)
+	err = db.Delete(t.Context(), key)
+	require.ErrorIs(t, err, zerokv.ErrClosed)
+	err = db.Close()
+	require.ErrorIs(t, err, zerokv.ErrClosed)
+}
+
+// testGetOwnership tests that values returned by Get belong to the caller.
+func testGetOwnership(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	key := helpers.RandomBytes(16)
+	value := helpers.RandomBytes(32)
+	require.NoError(t, db.Put(t.Context(), key, value))
+	first, err := db.Get(t.Context(), key)
+	require.NoError(t, err)
+	// unrelated reads and writes must not change a slice already returned
+	other := helpers.RandomBytes(16)
+	require.NoError(t, db.Put(t.Context(), other, helpers.RandomBytes(32)))
+	_, err = db.Get(t.Context(), other)
+	require.NoError(t, err)
+	require.Equal(t, value, first)
+	// mutating a returned slice must not corrupt stored data
+	for i := range first {
+		first[i] ^= 0xFF
+	}
+	second, err := db.Get(t.Context(), key)
+	require.NoError(t, err)
+	require.Equal(t, value, second)
+}
+
+// testBatchInputOwnership tests that buffers passed to a batch can be reused before Commit.
+func testBatchInputOwnership(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	batch := db.Batch()
+	key := []byte("batch_key")
+	value := []byte("batch_value")
+	require.NoError(t, batch.Put(key, value))
+	copy(key, "XXXXXXXXX")
+	copy(value, "YYYYYYYYYYY")
+	require.NoError(t, batch.Commit(t.Context()))
+	got, err := db.Get(t.Context(), []byte("batch_key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("batch_value"), got)
+	_, err = db.Get(t.Context(), key)
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+}
+
+// testGetFunc tests zero-copy reads through GetFunc.
+func testGetFunc(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	key := helpers.RandomBytes(16)
+	value := helpers.RandomBytes(32)
+	require.NoError(t, db.Put(t.Context(), key, value))
+	var got []byte
+	err := db.GetFunc(t.Context(), key, func(val []byte) error {
+		got = append(got, val...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, value, got)
+	// errors from the callback are returned unchanged
+	errStop := errors.New("stop")
+	err = db.GetFunc(t.Context(), key, func(val []byte) error {
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	called := false
+	err = db.GetFunc(t.Context(), helpers.RandomBytes(16), func(val []byte) error {
+		called = true
+		return nil
+	})
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	require.False(t, called)
+}
+
+// testHas tests existence checks for single and multiple keys.
+func testHas(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	present := [][]byte{[]byte("has_a"), []byte("has_c"), []byte("has_e")}
+	for _, key := range present {
+		require.NoError(t, db.Put(t.Context(), key, helpers.RandomBytes(1024)))
+	}
+	ok, err := db.Has(t.Context(), present[1])
+	require.NoError(t, err)
+	require.True(t, ok)
+	// a missing key sorting right after an existing one
+	ok, err = db.Has(t.Context(), []byte("has_c0"))
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, db.Delete(t.Context(), present[1]))
+	ok, err = db.Has(t.Context(), present[1])
+	require.NoError(t, err)
+	require.False(t, ok)
+	found, err := db.HasMany(t.Context(), [][]byte{
+		[]byte("has_e"), []byte("has_a"), []byte("has_b"), []byte("has_c"), []byte("has_z"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, true, false, false, false}, found)
+}
+
+// testGetManyPutMany tests multi-key writes and reads with per-key not-found reporting.
+func testGetManyPutMany(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := make([][]byte, 20)
+	values := make([][]byte, 20)
+	for i := range keys {
+		keys[i] = helpers.RandomBytes(16)
+		values[i] = helpers.RandomBytes(32)
+	}
+	require.NoError(t, db.PutMany(t.Context(), keys, values))
+	missing := helpers.RandomBytes(16)
+	query := append([][]byte{missing}, keys...)
+	got, errs := db.GetMany(t.Context(), query)
+	require.Len(t, got, len(query))
+	require.Len(t, errs, len(query))
+	require.ErrorIs(t, errs[0], zerokv.ErrNotFound)
+	require.Nil(t, got[0])
+	for i := range keys {
+		require.NoError(t, errs[i+1])
+		require.Equal(t, values[i], got[i+1])
+	}
+	err := db.PutMany(t.Context(), keys, values[:5])
+	require.ErrorIs(t, err, zerokv.ErrLengthMismatch)
+	// a failure of the whole read is reported for every key
+	require.NoError(t, db.Close())
+	_, errs = db.GetMany(t.Context(), keys[:3])
+	for _, err := range errs {
+		require.ErrorIs(t, err, zerokv.ErrClosed)
+	}
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvDeleteRange(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestDeleteRange",
+			fn: func(t *testing.T, name string) {
+				testDeleteRange(t, name)
+			}}, {
+			name: "TestDeleteRangeOpen",
+			fn: func(t *testing.T, name string) {
+				testDeleteRangeOpen(t, name)
+			}}, {
+			name: "TestDeletePrefix",
+			fn: func(t *testing.T, name string) {
+				testDeletePrefix(t, name)
+			}}, {
+			name: "TestDeletePrefixConcurrentWrites",
+			fn: func(t *testing.T, name string) {
+				testDeletePrefixConcurrentWrites(t, name)
+			}}, {
+			name: "TestBatchDeleteRange",
+			fn: func(t *testing.T, name string) {
+				testBatchDeleteRange(t, name)
+			}}, {
+			name: "TestBatchDeleteRangeAtCommit",
+			fn: func(t *testing.T, name string) {
+				testBatchDeleteRangeAtCommit(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+func testDeleteRange(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 10)
+	require.NoError(t, db.DeleteRange(t.Context(), []byte("key_03"), []byte("key_07")))
+	want := append(append([][]byte{}, keys[:3]...), keys[7:]...)
+	require.Equal(t, want, collectKeys(t, db.Scan(nil)))
+	// an empty or inverted range deletes nothing
+	require.NoError(t, db.DeleteRange(t.Context(), []byte("key_08"), []byte("key_08")))
+	require.NoError(t, db.DeleteRange(t.Context(), []byte("key_09"), []byte("key_01")))
+	require.Equal(t, want, collectKeys(t, db.Scan(nil)))
+}
+
+func testDeleteRangeOpen(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 10)
+	require.NoError(t, db.Put(t.Context(), []byte{0xFF, 0xFF}, []byte("last")))
+	require.NoError(t, db.DeleteRange(t.Context(), []byte("key_05"), nil))
+	require.Equal(t, keys[:5], collectKeys(t, db.Scan(nil)))
+	require.NoError(t, db.DeleteRange(t.Context(), nil, []byte("key_02")))
+	require.Equal(t, keys[2:5], collectKeys(t, db.Scan(nil)))
+}
+
+// testDeletePrefixConcurrentWrites tests that writers outside the prefix keep succeeding while it is deleted.
+func testDeletePrefixConcurrentWrites(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	batch := db.Batch()
+	for i := range 2000 {
+		require.NoError(t, batch.Put([]byte(fmt.Sprintf("old_%04d", i)), []byte("v")))
+	}
+	require.NoError(t, batch.Commit(t.Context()))
+	done := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if err := db.Put(t.Context(), []byte(fmt.Sprintf("new_%06d", i)), []byte("v"), zerokv.WriteOptions{}); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	for range 5 {
+		require.NoError(t, db.DeletePrefix(t.Context(), []byte("old_")))
+	}
+	close(done)
+	require.NoError(t, <-errs)
+	require.Empty(t, collectKeys(t, db.Scan([]byte("old_"))))
+}
+
+func testDeletePrefix(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	for _, key := range []string{"tenant_a/1", "tenant_a/2", "tenant_ab/1", "tenant_b/1"} {
+		require.NoError(t, db.Put(t.Context(), []byte(key), []byte("v")))
+	}
+	require.NoError(t, db.DeletePrefix(t.Context(), []byte("tenant_a/")))
+	require.Equal(t, [][]byte{[]byte("tenant_ab/1"), []byte("tenant_b/1")}, collectKeys(t, db.Scan(nil)))
+	require.ErrorIs(t, db.DeletePrefix(t.Context(), nil), zerokv.ErrEmptyKey)
+	// the database stays writable under a dropped prefix
+	require.NoError(t, db.Put(t.Context(), []byte("tenant_a/3"), []byte("v")))
+	found, err := db.Has(t.Context(), []byte("tenant_a/3"))
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func testBatchDeleteRange(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 4)
+	batch := db.Batch()
+	// keys put before the delete are covered, keys put after it survive
+	require.NoError(t, batch.Put([]byte("key_10"), []byte("staged")))
+	require.NoError(t, batch.Put([]byte("other_1"), []byte("kept")))
+	require.NoError(t, batch.DeletePrefix([]byte("key_")))
+	require.NoError(t, batch.Put([]byte("key_11"), []byte("after")))
+	require.ErrorIs(t, batch.DeletePrefix(nil), zerokv.ErrEmptyKey)
+	// keys are untouched until Commit
+	require.Equal(t, keys, collectKeys(t, db.Scan([]byte("key_"))))
+	require.NoError(t, batch.Commit(t.Context()))
+	require.Equal(t, [][]byte{[]byte("key_11"), []byte("other_1")}, collectKeys(t, db.Scan(nil)))
+}
+
+func testBatchDeleteRangeAtCommit(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	for _, key := range []string{"a", "k1", "m1"} {
+		require.NoError(t, db.Put(t.Context(), []byte(key), []byte("v")))
+	}
+	batch := db.Batch()
+	defer batch.Discard()
+	require.NoError(t, batch.DeleteRange([]byte("k"), []byte("l")))
+	require.NoError(t, batch.DeleteRange([]byte("m"), nil))
+	require.NoError(t, batch.Put([]byte("m5"), []byte("after")))
+	// batch reads see the ranges before they are committed
+	_, err := batch.Get([]byte("k1"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	require.Equal(t, [][]byte{[]byte("a"), []byte("m5")}, collectKeys(t, batch.Scan(nil)))
+	// keys written before Commit are covered, past the end found when the range was staged too
+	require.NoError(t, db.Put(t.Context(), []byte("k5"), []byte("v")))
+	require.NoError(t, db.Put(t.Context(), []byte("m9"), []byte("v")))
+	require.NoError(t, batch.Commit(t.Context()))
+	require.Equal(t, [][]byte{[]byte("a"), []byte("m5")}, collectKeys(t, db.Scan(nil)))
+}
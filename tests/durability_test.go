@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvDurability(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestWriteOptions",
+			fn: func(t *testing.T, name string) {
+				testWriteOptions(t, name)
+			}}, {
+			name: "TestSyncReopen",
+			fn: func(t *testing.T, name string) {
+				testSyncReopen(t, name)
+			}}, {
+			name: "TestSyncErrors",
+			fn: func(t *testing.T, name string) {
+				testSyncErrors(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+var noSync = zerokv.WriteOptions{Sync: false}
+
+func testWriteOptions(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	for _, opts := range [][]zerokv.WriteOptions{nil, {noSync}, {{Sync: true}}} {
+		require.NoError(t, db.Put(t.Context(), []byte("key"), []byte("value"), opts...))
+		val, err := db.Get(t.Context(), []byte("key"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("value"), val)
+		require.NoError(t, db.Delete(t.Context(), []byte("key"), opts...))
+		_, err = db.Get(t.Context(), []byte("key"))
+		require.ErrorIs(t, err, zerokv.ErrNotFound)
+		batch := db.Batch()
+		require.NoError(t, batch.Put([]byte("batched"), []byte("value")))
+		require.NoError(t, batch.Commit(t.Context(), opts...))
+		found, err := db.Has(t.Context(), []byte("batched"))
+		require.NoError(t, err)
+		require.True(t, found)
+		require.ErrorIs(t, batch.Commit(t.Context(), opts...), zerokv.ErrBatchCommitted)
+	}
+	require.Equal(t, zerokv.DefaultWriteOptions, zerokv.WriteOptionsOf(nil))
+	require.Equal(t, noSync, zerokv.WriteOptionsOf([]zerokv.WriteOptions{{Sync: true}, noSync}))
+}
+
+func testSyncReopen(t *testing.T, name string) {
+	dir := t.TempDir()
+	db := helpers.OpenDB(t, name, dir)
+	keys := make([][]byte, 20)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("key_%02d", i))
+		require.NoError(t, db.Put(t.Context(), keys[i], []byte("value"), noSync))
+	}
+	require.NoError(t, db.Delete(t.Context(), keys[0], noSync))
+	require.NoError(t, db.Sync(t.Context()))
+	require.NoError(t, db.Close())
+	db = helpers.OpenDB(t, name, dir)
+	defer db.Close()
+	require.Equal(t, keys[1:], collectKeys(t, db.Scan(nil)))
+}
+
+func testSyncErrors(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	require.ErrorIs(t, db.Sync(ctx), context.Canceled)
+	require.NoError(t, db.Close())
+	require.ErrorIs(t, db.Sync(t.Context()), zerokv.ErrClosed)
+}
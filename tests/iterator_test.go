@@ -2,8 +2,10 @@ package tests
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/rawbytedev/zerokv"
 	"github.com/rawbytedev/zerokv/helpers"
@@ -39,6 +41,71 @@ func TestZeroKvIterator(t *testing.T) {
 			fn: func(t *testing.T, name string) {
 				testIterateHasKey(t, name)
 			},
+		}, {
+			name: "TestScanRangeBounds",
+			fn: func(t *testing.T, name string) {
+				testScanRangeBounds(t, name)
+			},
+		}, {
+			name: "TestScanRangeLimit",
+			fn: func(t *testing.T, name string) {
+				testScanRangeLimit(t, name)
+			},
+		}, {
+			name: "TestScanRangeReverse",
+			fn: func(t *testing.T, name string) {
+				testScanRangeReverse(t, name)
+			},
+		}, {
+			name: "TestReversePrefixOrdering",
+			fn: func(t *testing.T, name string) {
+				testReversePrefixOrdering(t, name)
+			},
+		}, {
+			name: "TestIteratorSeek",
+			fn: func(t *testing.T, name string) {
+				testIteratorSeek(t, name)
+			},
+		}, {
+			name: "TestIteratorSeekReverse",
+			fn: func(t *testing.T, name string) {
+				testIteratorSeekReverse(t, name)
+			},
+		}, {
+			name: "TestScanFullKeyspace",
+			fn: func(t *testing.T, name string) {
+				testScanFullKeyspace(t, name)
+			},
+		}, {
+			name: "TestScanMaxBytePrefix",
+			fn: func(t *testing.T, name string) {
+				testScanMaxBytePrefix(t, name)
+			},
+		}, {
+			name: "TestScanErroredIterator",
+			fn: func(t *testing.T, name string) {
+				testScanErroredIterator(t, name)
+			},
+		}, {
+			name: "TestIteratorOwnership",
+			fn: func(t *testing.T, name string) {
+				testIteratorOwnership(t, name)
+			},
+		}, {
+			name: "TestForEach",
+			fn: func(t *testing.T, name string) {
+				testForEach(t, name)
+			},
+		}, {
+			name: "TestScanRangeKeysOnly",
+			fn: func(t *testing.T, name string) {
+				testScanRangeKeysOnly(t, name)
+			},
+		}, {
+			name: "TestScanKeys",
+			fn: func(t *testing.T, name string) {
+				testScanKeys(t, name)
+			},
 		},
 	}
 	for i := range dbs {
@@ -51,6 +118,30 @@ func TestZeroKvIterator(t *testing.T) {
 	}
 }
 
+// FillOrdered stores n keys named key_00, key_01, ... so ordering can be asserted.
+func FillOrdered(t *testing.T, db zerokv.Core, n int) [][]byte {
+	keys := make([][]byte, n)
+	for i := range n {
+		keys[i] = []byte(fmt.Sprintf("key_%02d", i))
+		err := db.Put(t.Context(), keys[i], []byte(fmt.Sprintf("value_%02d", i)))
+		if err != nil {
+			t.Fatalf("Failed to put key-value pair: %v", err)
+		}
+	}
+	return keys
+}
+
+// collectKeys drains an iterator and returns the keys it produced.
+func collectKeys(t *testing.T, it zerokv.Iterator) [][]byte {
+	defer it.Release()
+	var keys [][]byte
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+	require.NoError(t, it.Error())
+	return keys
+}
+
 func testIterateValues(t *testing.T, name string) {
 	db := helpers.SetupDB(t, name)
 	_, _ = FillValues(t, db)
@@ -84,3 +175,271 @@ func testIterateHasKey(t *testing.T, name string) {
 	defer db.Close()
 	defer it.Release()
 }
+
+func testScanRangeBounds(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 20)
+	got := collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Start: keys[5], End: keys[10]}))
+	require.Equal(t, keys[5:10], got)
+	// open ended bounds
+	got = collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Start: keys[15]}))
+	require.Equal(t, keys[15:], got)
+	got = collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{End: keys[3]}))
+	require.Equal(t, keys[:3], got)
+	// bounds that are not stored keys
+	got = collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Start: []byte("key_05a"), End: []byte("key_08a")}))
+	require.Equal(t, keys[6:9], got)
+}
+
+func testScanRangeLimit(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 20)
+	got := collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Start: keys[2], Limit: 4}))
+	require.Equal(t, keys[2:6], got)
+	got = collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{End: keys[10], Limit: 3, Reverse: true}))
+	require.Equal(t, [][]byte{keys[9], keys[8], keys[7]}, got)
+}
+
+func testScanRangeReverse(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 20)
+	got := collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Start: keys[5], End: keys[10], Reverse: true}))
+	require.Equal(t, [][]byte{keys[9], keys[8], keys[7], keys[6], keys[5]}, got)
+	got = collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Reverse: true}))
+	require.Len(t, got, 20)
+	require.Equal(t, keys[19], got[0])
+	require.Equal(t, keys[0], got[19])
+}
+
+func testScanRangeKeysOnly(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 5)
+	it := db.ScanRange(t.Context(), zerokv.ScanOptions{KeysOnly: true})
+	defer it.Release()
+	for i := range 5 {
+		require.True(t, it.Next())
+		require.Equal(t, keys[i], it.Key())
+		require.Nil(t, it.Value())
+	}
+	require.False(t, it.Next())
+	require.NoError(t, it.Error())
+}
+
+// testScanKeys tests keys-only scans still hide expired keys without reading values.
+func testScanKeys(t *testing.T, name string) {
+	clock := helpers.NewFakeClock()
+	db := helpers.SetupDBWithClock(t, name, clock.Now)
+	defer db.Close()
+	keys := FillOrdered(t, db, 3)
+	require.NoError(t, db.PutWithTTL(t.Context(), []byte("key_01a"), []byte("v"), 10*time.Second))
+	require.NoError(t, db.Put(t.Context(), []byte("other"), []byte("v")))
+	require.Equal(t, [][]byte{keys[0], keys[1], []byte("key_01a"), keys[2]}, collectKeys(t, db.ScanKeys(t.Context(), []byte("key_"))))
+	clock.Advance(time.Minute)
+	it := db.ScanKeys(t.Context(), []byte("key_"))
+	defer it.Release()
+	for i := range 3 {
+		require.True(t, it.Next())
+		require.Equal(t, keys[i], it.Key())
+		require.Nil(t, it.Value())
+	}
+	require.False(t, it.Next())
+	require.NoError(t, it.Error())
+}
+
+func testReversePrefixOrdering(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	var want [][]byte
+	for _, prefix := range []string{"a_", "b_", "c_"} {
+		for i := range 10 {
+			key := []byte(fmt.Sprintf("%s%02d", prefix, i))
+			require.NoError(t, db.Put(t.Context(), key, helpers.RandomBytes(8)))
+			if prefix == "b_" {
+				want = append(want, key)
+			}
+		}
+	}
+	forward := collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Prefix: []byte("b_")}))
+	require.Equal(t, want, forward)
+	reverse := collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Prefix: []byte("b_"), Reverse: true}))
+	require.Len(t, reverse, len(want))
+	for i := range reverse {
+		require.Equal(t, want[len(want)-1-i], reverse[i])
+	}
+	// latest N entries under a prefix
+	latest := collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Prefix: []byte("b_"), Reverse: true, Limit: 3}))
+	require.Equal(t, [][]byte{want[9], want[8], want[7]}, latest)
+	// prefix narrowed further by Start and End
+	window := collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{
+		Prefix:  []byte("b_"),
+		Start:   []byte("a_05"),
+		End:     []byte("b_04"),
+		Reverse: true,
+	}))
+	require.Equal(t, [][]byte{want[3], want[2], want[1], want[0]}, window)
+}
+
+func testIteratorSeek(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 20)
+	it := db.ScanRange(t.Context(), zerokv.ScanOptions{Start: keys[2], End: keys[15]})
+	defer it.Release()
+	require.True(t, it.Seek(keys[7]))
+	require.Equal(t, keys[7], it.Key())
+	require.True(t, it.Next())
+	require.Equal(t, keys[8], it.Key())
+	// seeking between stored keys lands on the next one
+	require.True(t, it.Seek([]byte("key_10a")))
+	require.Equal(t, keys[11], it.Key())
+	// keys before the scan are clamped to Start, keys past End are exhausted
+	require.True(t, it.Seek(keys[0]))
+	require.Equal(t, keys[2], it.Key())
+	require.False(t, it.Seek(keys[16]))
+	require.True(t, it.First())
+	require.Equal(t, keys[2], it.Key())
+	require.NoError(t, it.Error())
+	// resuming a prefix scan from a known key
+	it = db.Scan([]byte("key_"))
+	defer it.Release()
+	var resumed [][]byte
+	for ok := it.Seek(keys[17]); ok; ok = it.Next() {
+		resumed = append(resumed, it.Key())
+	}
+	require.Equal(t, keys[17:], resumed)
+	// Limit restarts from the seek position
+	it = db.ScanRange(t.Context(), zerokv.ScanOptions{Limit: 2})
+	defer it.Release()
+	require.True(t, it.Seek(keys[10]))
+	require.True(t, it.Next())
+	require.Equal(t, keys[11], it.Key())
+	require.False(t, it.Next())
+}
+
+func testIteratorSeekReverse(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 20)
+	it := db.ScanRange(t.Context(), zerokv.ScanOptions{Start: keys[2], End: keys[15], Reverse: true})
+	defer it.Release()
+	require.True(t, it.Seek(keys[7]))
+	require.Equal(t, keys[7], it.Key())
+	require.True(t, it.Next())
+	require.Equal(t, keys[6], it.Key())
+	require.True(t, it.Seek([]byte("key_10a")))
+	require.Equal(t, keys[10], it.Key())
+	// End is exclusive, seeking past it lands on the last key of the scan
+	require.True(t, it.Seek(keys[19]))
+	require.Equal(t, keys[14], it.Key())
+	require.False(t, it.Seek(keys[1]))
+	require.True(t, it.First())
+	require.Equal(t, keys[14], it.Key())
+	require.NoError(t, it.Error())
+}
+
+func testScanFullKeyspace(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 10)
+	require.NoError(t, db.Put(t.Context(), []byte{0xFF, 0xFF}, []byte("last")))
+	keys = append(keys, []byte{0xFF, 0xFF})
+	require.Equal(t, keys, collectKeys(t, db.Scan(nil)))
+	require.Equal(t, keys, collectKeys(t, db.Scan([]byte{})))
+}
+
+func testScanMaxBytePrefix(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	stored := [][]byte{
+		{0xFE, 0xFF},
+		{0xFF, 0x01},
+		{0xFF, 0xFF, 0x02},
+		{0xFF, 0xFF, 0xFF},
+	}
+	for _, key := range stored {
+		require.NoError(t, db.Put(t.Context(), key, helpers.RandomBytes(8)))
+	}
+	require.Equal(t, stored[1:], collectKeys(t, db.Scan([]byte{0xFF})))
+	require.Equal(t, stored[2:], collectKeys(t, db.Scan([]byte{0xFF, 0xFF})))
+	require.Equal(t, stored[:1], collectKeys(t, db.Scan([]byte{0xFE})))
+	// prefix ending in 0xFF followed by a regular byte
+	require.Equal(t, stored[:1], collectKeys(t, db.Scan([]byte{0xFE, 0xFF})))
+}
+
+func testScanErroredIterator(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	FillOrdered(t, db, 5)
+	require.NoError(t, db.Close())
+	it := db.Scan(nil)
+	require.NotNil(t, it)
+	require.False(t, it.Next())
+	require.Nil(t, it.Key())
+	require.Nil(t, it.Value())
+	require.ErrorIs(t, it.Error(), zerokv.ErrClosed)
+	it.Release()
+	it = db.ScanRange(t.Context(), zerokv.ScanOptions{Reverse: true})
+	require.False(t, it.Seek([]byte("key_")))
+	require.ErrorIs(t, it.Error(), zerokv.ErrClosed)
+	it.Release()
+}
+
+func testIteratorOwnership(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 10)
+	it := db.Scan([]byte("key_"))
+	var gotKeys, gotValues [][]byte
+	for it.Next() {
+		gotKeys = append(gotKeys, it.Key())
+		gotValues = append(gotValues, it.Value())
+	}
+	require.NoError(t, it.Error())
+	it.Release()
+	// slices kept across Next and Release must still hold their entry
+	require.Equal(t, keys, gotKeys)
+	for i := range gotValues {
+		require.Equal(t, []byte(fmt.Sprintf("value_%02d", i)), gotValues[i])
+		for j := range gotValues[i] {
+			gotValues[i][j] = 0
+		}
+		gotKeys[i][0] = 0
+	}
+	// mutating them must not corrupt stored data
+	it = db.Scan([]byte("key_"))
+	defer it.Release()
+	for i := 0; it.Next(); i++ {
+		require.Equal(t, keys[i], it.Key())
+		require.Equal(t, []byte(fmt.Sprintf("value_%02d", i)), it.Value())
+	}
+}
+
+func testForEach(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 10)
+	_, _ = FillValues(t, db)
+	var got [][]byte
+	err := db.ForEach(t.Context(), []byte("key_"), func(key, val []byte) error {
+		require.Equal(t, []byte(fmt.Sprintf("value_%02d", len(got))), val)
+		got = append(got, bytes.Clone(key))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, keys, got)
+	// an error from the callback stops the scan
+	errStop := errors.New("stop")
+	count := 0
+	err = db.ForEach(t.Context(), nil, func(key, val []byte) error {
+		count++
+		if count == 3 {
+			return errStop
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, 3, count)
+}
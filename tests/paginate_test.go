@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvPaginate(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestPaginateForward",
+			fn: func(t *testing.T, name string) {
+				testPaginateForward(t, name)
+			}}, {
+			name: "TestPaginateReverse",
+			fn: func(t *testing.T, name string) {
+				testPaginateReverse(t, name)
+			}}, {
+			name: "TestPaginateStableInserts",
+			fn: func(t *testing.T, name string) {
+				testPaginateStableInserts(t, name)
+			}}, {
+			name: "TestPaginateInvalidToken",
+			fn: func(t *testing.T, name string) {
+				testPaginateInvalidToken(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+// pageKeys returns the keys of a page.
+func pageKeys(page zerokv.Page) [][]byte {
+	keys := make([][]byte, len(page.Items))
+	for i, item := range page.Items {
+		keys[i] = item.Key
+	}
+	return keys
+}
+
+func testPaginateForward(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 7)
+	require.NoError(t, db.Put(t.Context(), []byte("other"), []byte("v")))
+	var got [][]byte
+	token := ""
+	pages := 0
+	for {
+		page, err := zerokv.Paginate(t.Context(), db, []byte("key_"), 3, token)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Items), 3)
+		got = append(got, pageKeys(page)...)
+		pages++
+		if page.Next == "" {
+			break
+		}
+		token = page.Next
+	}
+	require.Equal(t, keys, got)
+	require.Equal(t, 3, pages)
+	require.Equal(t, []byte("value_00"), mustPage(t, db, "", false).Items[0].Value)
+	// an exact multiple of the page size does not end on an empty page
+	page, err := zerokv.Paginate(t.Context(), db, []byte("key_"), 7, "")
+	require.NoError(t, err)
+	require.Len(t, page.Items, 7)
+	require.Empty(t, page.Next)
+	_, err = zerokv.Paginate(t.Context(), db, []byte("key_"), 0, "")
+	require.Error(t, err)
+}
+
+// mustPage fetches a page of three key_ entries.
+func mustPage(t *testing.T, db zerokv.Core, token string, reverse bool) zerokv.Page {
+	paginate := zerokv.Paginate
+	if reverse {
+		paginate = zerokv.PaginateReverse
+	}
+	page, err := paginate(t.Context(), db, []byte("key_"), 3, token)
+	require.NoError(t, err)
+	return page
+}
+
+func testPaginateReverse(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 7)
+	var got [][]byte
+	page := mustPage(t, db, "", true)
+	got = append(got, pageKeys(page)...)
+	for page.Next != "" {
+		page = mustPage(t, db, page.Next, true)
+		got = append(got, pageKeys(page)...)
+	}
+	slices.Reverse(keys)
+	require.Equal(t, keys, got)
+}
+
+func testPaginateStableInserts(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillOrdered(t, db, 6)
+	first := mustPage(t, db, "", false)
+	require.Equal(t, [][]byte{[]byte("key_00"), []byte("key_01"), []byte("key_02")}, pageKeys(first))
+	// keys before the cursor are not seen again, keys after it are picked up
+	require.NoError(t, db.Put(t.Context(), []byte("key_01a"), []byte("v")))
+	require.NoError(t, db.Put(t.Context(), []byte("key_02a"), []byte("v")))
+	second := mustPage(t, db, first.Next, false)
+	require.Equal(t, [][]byte{[]byte("key_02a"), []byte("key_03"), []byte("key_04")}, pageKeys(second))
+	// the cursor key itself may go away
+	require.NoError(t, db.Delete(t.Context(), []byte("key_04")))
+	third := mustPage(t, db, second.Next, false)
+	require.Equal(t, [][]byte{[]byte("key_05")}, pageKeys(third))
+	require.Empty(t, third.Next)
+}
+
+func testPaginateInvalidToken(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillOrdered(t, db, 6)
+	next := mustPage(t, db, "", false).Next
+	require.NotEmpty(t, next)
+	raw, err := base64.RawURLEncoding.DecodeString(next)
+	require.NoError(t, err)
+	raw[2] ^= 0xFF
+	tampered := base64.RawURLEncoding.EncodeToString(raw)
+	for _, token := range []string{tampered, "not a token", next[:len(next)-2]} {
+		_, err = zerokv.Paginate(t.Context(), db, []byte("key_"), 3, token)
+		require.ErrorIs(t, err, zerokv.ErrInvalidToken)
+	}
+	// tokens are bound to their prefix and direction
+	_, err = zerokv.Paginate(t.Context(), db, []byte("key_0"), 3, next)
+	require.ErrorIs(t, err, zerokv.ErrInvalidToken)
+	_, err = zerokv.PaginateReverse(t.Context(), db, []byte("key_"), 3, next)
+	require.ErrorIs(t, err, zerokv.ErrInvalidToken)
+	// a token from another secret is rejected
+	_, err = zerokv.NewPaginator([]byte("secret")).Paginate(t.Context(), db, []byte("key_"), 3, next)
+	require.ErrorIs(t, err, zerokv.ErrInvalidToken)
+}
@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvParallelScan(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestParallelScanAll",
+			fn: func(t *testing.T, name string) {
+				testParallelScanAll(t, name)
+			}}, {
+			name: "TestParallelScanError",
+			fn: func(t *testing.T, name string) {
+				testParallelScanError(t, name)
+			}}, {
+			name: "TestParallelScanContext",
+			fn: func(t *testing.T, name string) {
+				testParallelScanContext(t, name)
+			}}, {
+			name: "TestEvenSplits",
+			fn: func(t *testing.T, name string) {
+				testEvenSplits(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+// FillItems stores n keys named item_0000, item_0001, ... with their index as value.
+func FillItems(t *testing.T, db zerokv.Core, n int) [][]byte {
+	keys := make([][]byte, n)
+	batch := db.Batch()
+	defer batch.Discard()
+	for i := range n {
+		keys[i] = []byte(fmt.Sprintf("item_%04d", i))
+		require.NoError(t, batch.Put(keys[i], []byte(fmt.Sprint(i))))
+	}
+	require.NoError(t, batch.Commit(t.Context()))
+	return keys
+}
+
+func testParallelScanAll(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillItems(t, db, 500)
+	FillOrdered(t, db, 5)
+	for _, workers := range []int{0, 1, 4} {
+		var mu sync.Mutex
+		var got [][]byte
+		err := db.ParallelScan(t.Context(), []byte("item_"), workers, func(key, value []byte) error {
+			var i int
+			_, err := fmt.Sscanf(string(key), "item_%04d", &i)
+			require.NoError(t, err)
+			require.Equal(t, []byte(fmt.Sprint(i)), value)
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, key)
+			return nil
+		})
+		require.NoError(t, err)
+		slices.SortFunc(got, bytes.Compare)
+		require.Equal(t, keys, got)
+	}
+}
+
+func testParallelScanError(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillItems(t, db, 500)
+	errStop := errors.New("stop")
+	var calls atomic.Int64
+	err := db.ParallelScan(t.Context(), []byte("item_"), 4, func(key, value []byte) error {
+		if calls.Add(1) == 10 {
+			return errStop
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Less(t, calls.Load(), int64(500))
+}
+
+func testParallelScanContext(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillItems(t, db, 50)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	err := db.ParallelScan(ctx, []byte("item_"), 4, func(key, value []byte) error {
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func testEvenSplits(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillItems(t, db, 200)
+	splits, err := zerokv.EvenSplits(t.Context(), db, []byte("item_"), 8)
+	require.NoError(t, err)
+	require.NotEmpty(t, splits)
+	require.LessOrEqual(t, len(splits), 7)
+	require.True(t, slices.IsSortedFunc(splits, bytes.Compare))
+	require.Positive(t, bytes.Compare(splits[0], keys[0]))
+	require.LessOrEqual(t, bytes.Compare(splits[len(splits)-1], keys[len(keys)-1]), 0)
+	// partitions cover every key exactly once
+	var count atomic.Int64
+	require.NoError(t, zerokv.ScanPartitions(t.Context(), db, []byte("item_"), splits, 3, func(key, value []byte) error {
+		count.Add(1)
+		return nil
+	}))
+	require.Equal(t, int64(len(keys)), count.Load())
+	// nothing to split under an empty prefix
+	splits, err = zerokv.EvenSplits(t.Context(), db, []byte("none_"), 8)
+	require.NoError(t, err)
+	require.Empty(t, splits)
+}
+
+// TestSizedSplits tests that split keys balance the estimated sizes.
+func TestSizedSplits(t *testing.T) {
+	points := []zerokv.SplitPoint{
+		{Key: []byte("d"), Size: 10},
+		{Key: []byte("a"), Size: 10},
+		{Key: []byte("b"), Size: 10},
+		{Key: []byte("b"), Size: 0},
+		{Key: []byte("c"), Size: 70},
+	}
+	require.Equal(t, [][]byte{[]byte("c")}, zerokv.SizedSplits(points, 2))
+	require.Equal(t, [][]byte{[]byte("b"), []byte("c")}, zerokv.SizedSplits(points, 5))
+	require.Nil(t, zerokv.SizedSplits(points, 1))
+	require.Nil(t, zerokv.SizedSplits([]zerokv.SplitPoint{{Key: []byte("a")}}, 4))
+}
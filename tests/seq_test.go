@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvSeq(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestSeqAll",
+			fn: func(t *testing.T, name string) {
+				testSeqAll(t, name)
+			}}, {
+			name: "TestSeqKeysRange",
+			fn: func(t *testing.T, name string) {
+				testSeqKeysRange(t, name)
+			}}, {
+			name: "TestSeqBreakReleases",
+			fn: func(t *testing.T, name string) {
+				testSeqBreakReleases(t, name)
+			}}, {
+			name: "TestSeqError",
+			fn: func(t *testing.T, name string) {
+				testSeqError(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+// releaseCounter wraps a Core and counts the iterators it hands out that were released.
+type releaseCounter struct {
+	zerokv.Core
+	opened, released int
+}
+
+func (c *releaseCounter) ScanRange(ctx context.Context, opts zerokv.ScanOptions) zerokv.Iterator {
+	c.opened++
+	return &countedIterator{Iterator: c.Core.ScanRange(ctx, opts), counter: c}
+}
+
+type countedIterator struct {
+	zerokv.Iterator
+	counter *releaseCounter
+}
+
+func (it *countedIterator) Release() {
+	it.counter.released++
+	it.Iterator.Release()
+}
+
+func testSeqAll(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 5)
+	entries, errf := zerokv.All(t.Context(), db, []byte("key_"))
+	var got [][]byte
+	for key, val := range entries {
+		require.Equal(t, fmt.Sprintf("value_%s", key[len("key_"):]), string(val))
+		got = append(got, key)
+	}
+	require.NoError(t, errf())
+	require.Equal(t, keys, got)
+	// the sequence can be ranged over again
+	count := 0
+	for range entries {
+		count++
+	}
+	require.Equal(t, 5, count)
+}
+
+func testSeqKeysRange(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 10)
+	names, errf := zerokv.Keys(t.Context(), db, []byte("key_0"))
+	var got [][]byte
+	for key := range names {
+		got = append(got, key)
+	}
+	require.NoError(t, errf())
+	require.Equal(t, keys, got)
+	entries, errf := zerokv.Range(t.Context(), db, zerokv.ScanOptions{
+		Start:   []byte("key_02"),
+		End:     []byte("key_06"),
+		Reverse: true,
+	})
+	got = nil
+	for key := range entries {
+		got = append(got, key)
+	}
+	require.NoError(t, errf())
+	require.Equal(t, [][]byte{keys[5], keys[4], keys[3], keys[2]}, got)
+}
+
+func testSeqBreakReleases(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillOrdered(t, db, 10)
+	counter := &releaseCounter{Core: db}
+	entries, errf := zerokv.All(t.Context(), counter, nil)
+	for range entries {
+		break
+	}
+	require.NoError(t, errf())
+	names, _ := zerokv.Keys(t.Context(), counter, nil)
+	require.Panics(t, func() {
+		for range names {
+			panic("boom")
+		}
+	})
+	require.Equal(t, 2, counter.opened)
+	require.Equal(t, 2, counter.released)
+}
+
+func testSeqError(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	FillOrdered(t, db, 10)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	entries, errf := zerokv.All(ctx, db, nil)
+	seen := 0
+	for range entries {
+		seen++
+		if seen == 3 {
+			cancel()
+		}
+	}
+	require.Equal(t, 3, seen)
+	require.ErrorIs(t, errf(), context.Canceled)
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvSnapshot(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestSnapshotGet",
+			fn: func(t *testing.T, name string) {
+				testSnapshotGet(t, name)
+			}}, {
+			name: "TestSnapshotScan",
+			fn: func(t *testing.T, name string) {
+				testSnapshotScan(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+// testSnapshotGet tests that writes made after a snapshot are invisible through it.
+func testSnapshotGet(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	require.NoError(t, db.Put(t.Context(), []byte("updated"), []byte("before")))
+	require.NoError(t, db.Put(t.Context(), []byte("deleted"), []byte("before")))
+	snap, err := db.Snapshot()
+	require.NoError(t, err)
+	defer snap.Release()
+	require.NoError(t, db.Put(t.Context(), []byte("updated"), []byte("after")))
+	require.NoError(t, db.Delete(t.Context(), []byte("deleted")))
+	batch := db.Batch()
+	require.NoError(t, batch.Put([]byte("created"), []byte("after")))
+	require.NoError(t, batch.Commit(t.Context()))
+	value, err := snap.Get(t.Context(), []byte("updated"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("before"), value)
+	value, err = snap.Get(t.Context(), []byte("deleted"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("before"), value)
+	_, err = snap.Get(t.Context(), []byte("created"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	// the live database sees the new state
+	value, err = db.Get(t.Context(), []byte("updated"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("after"), value)
+}
+
+// testSnapshotScan tests that several scans of one snapshot see the same data.
+func testSnapshotScan(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 10)
+	snap, err := db.Snapshot()
+	require.NoError(t, err)
+	defer snap.Release()
+	require.NoError(t, db.Put(t.Context(), []byte("key_99"), []byte("late")))
+	require.NoError(t, db.Delete(t.Context(), keys[0]))
+	first := snap.Scan([]byte("key_"))
+	second := snap.Scan([]byte("key_"))
+	for i := range keys {
+		require.True(t, first.Next())
+		require.True(t, second.Next())
+		require.Equal(t, keys[i], first.Key())
+		require.Equal(t, keys[i], second.Key())
+		require.Equal(t, []byte(fmt.Sprintf("value_%02d", i)), first.Value())
+	}
+	require.False(t, first.Next())
+	require.False(t, second.Next())
+	require.NoError(t, first.Error())
+	first.Release()
+	second.Release()
+	require.Len(t, collectKeys(t, db.Scan([]byte("key_"))), 10)
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvTTL(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestTTLGet",
+			fn: func(t *testing.T, name string) {
+				testTTLGet(t, name)
+			}}, {
+			name: "TestTTLScan",
+			fn: func(t *testing.T, name string) {
+				testTTLScan(t, name)
+			}}, {
+			name: "TestTTLBatch",
+			fn: func(t *testing.T, name string) {
+				testTTLBatch(t, name)
+			}}, {
+			name: "TestTTLOverwrite",
+			fn: func(t *testing.T, name string) {
+				testTTLOverwrite(t, name)
+			}}, {
+			name: "TestTTLSubSecond",
+			fn: func(t *testing.T, name string) {
+				testTTLSubSecond(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+const ttl = 10 * time.Second
+
+func testTTLGet(t *testing.T, name string) {
+	clock := helpers.NewFakeClock()
+	db := helpers.SetupDBWithClock(t, name, clock.Now)
+	defer db.Close()
+	key := []byte("session")
+	require.NoError(t, db.PutWithTTL(t.Context(), key, []byte("token"), ttl))
+	clock.Advance(ttl / 2)
+	val, err := db.Get(t.Context(), key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("token"), val)
+	clock.Advance(ttl)
+	_, err = db.Get(t.Context(), key)
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	found, err := db.Has(t.Context(), key)
+	require.NoError(t, err)
+	require.False(t, found)
+	err = db.GetFunc(t.Context(), key, func(val []byte) error { return nil })
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	_, errs := db.GetMany(t.Context(), [][]byte{key})
+	require.ErrorIs(t, errs[0], zerokv.ErrNotFound)
+}
+
+func testTTLScan(t *testing.T, name string) {
+	clock := helpers.NewFakeClock()
+	db := helpers.SetupDBWithClock(t, name, clock.Now)
+	defer db.Close()
+	require.NoError(t, db.Put(t.Context(), []byte("k_1"), []byte("a")))
+	require.NoError(t, db.PutWithTTL(t.Context(), []byte("k_2"), []byte("b"), ttl))
+	require.NoError(t, db.Put(t.Context(), []byte("k_3"), []byte("c")))
+	require.NoError(t, db.PutWithTTL(t.Context(), []byte("k_4"), []byte("d"), ttl))
+	clock.Advance(2 * ttl)
+	require.Equal(t, [][]byte{[]byte("k_1"), []byte("k_3")}, collectKeys(t, db.Scan([]byte("k_"))))
+	require.Equal(t, [][]byte{[]byte("k_3"), []byte("k_1")}, collectKeys(t, db.ScanRange(t.Context(), zerokv.ScanOptions{Prefix: []byte("k_"), Reverse: true})))
+	var seen []string
+	require.NoError(t, db.ForEach(t.Context(), []byte("k_"), func(key, val []byte) error {
+		seen = append(seen, string(key))
+		return nil
+	}))
+	require.Equal(t, []string{"k_1", "k_3"}, seen)
+}
+
+func testTTLBatch(t *testing.T, name string) {
+	clock := helpers.NewFakeClock()
+	db := helpers.SetupDBWithClock(t, name, clock.Now)
+	defer db.Close()
+	batch := db.Batch()
+	require.NoError(t, batch.PutWithTTL([]byte("short"), []byte("a"), ttl))
+	require.NoError(t, batch.PutWithTTL([]byte("long"), []byte("b"), 3*ttl))
+	require.NoError(t, batch.Commit(t.Context()))
+	clock.Advance(2 * ttl)
+	_, err := db.Get(t.Context(), []byte("short"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	val, err := db.Get(t.Context(), []byte("long"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), val)
+}
+
+func testTTLOverwrite(t *testing.T, name string) {
+	clock := helpers.NewFakeClock()
+	db := helpers.SetupDBWithClock(t, name, clock.Now)
+	defer db.Close()
+	key := []byte("cache")
+	require.NoError(t, db.PutWithTTL(t.Context(), key, []byte("v1"), ttl))
+	// a plain Put clears the expiry
+	require.NoError(t, db.Put(t.Context(), key, []byte("v2")))
+	// a non-positive ttl never expires
+	require.NoError(t, db.PutWithTTL(t.Context(), []byte("forever"), []byte("v"), 0))
+	clock.Advance(2 * ttl)
+	val, err := db.Get(t.Context(), key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("v2"), val)
+	_, err = db.Get(t.Context(), []byte("forever"))
+	require.NoError(t, err)
+}
+
+func testTTLSubSecond(t *testing.T, name string) {
+	clock := helpers.NewFakeClock()
+	db := helpers.SetupDBWithClock(t, name, clock.Now)
+	defer db.Close()
+	require.NoError(t, db.PutWithTTL(t.Context(), []byte("k_1"), []byte("a"), 500*time.Millisecond))
+	batch := db.Batch()
+	require.NoError(t, batch.PutWithTTL([]byte("k_2"), []byte("b"), 500*time.Millisecond))
+	require.NoError(t, batch.PutWithTTL([]byte("k_3"), []byte("c"), time.Hour))
+	require.NoError(t, batch.Commit(t.Context()))
+	clock.Advance(300 * time.Millisecond)
+	val, err := db.Get(t.Context(), []byte("k_2"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), val)
+	// expiring values read back without any expiry bookkeeping
+	require.NoError(t, db.GetFunc(t.Context(), []byte("k_1"), func(val []byte) error {
+		require.Equal(t, []byte("a"), val)
+		return nil
+	}))
+	it := db.Scan([]byte("k_"))
+	var vals []string
+	for it.Next() {
+		vals = append(vals, string(it.Value()))
+	}
+	require.NoError(t, it.Error())
+	it.Release()
+	require.Equal(t, []string{"a", "b", "c"}, vals)
+	clock.Advance(300 * time.Millisecond)
+	_, err = db.Get(t.Context(), []byte("k_1"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+	require.Equal(t, [][]byte{[]byte("k_3")}, collectKeys(t, db.Scan([]byte("k_"))))
+}
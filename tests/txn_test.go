@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/rawbytedev/zerokv"
+	"github.com/rawbytedev/zerokv/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroKvTxn(t *testing.T) {
+	dbs := []string{"badgerdb", "pebbledb"}
+	list_test := []test{
+		{
+			name: "TestTxnReadOwnWrites",
+			fn: func(t *testing.T, name string) {
+				testTxnReadOwnWrites(t, name)
+			}}, {
+			name: "TestTxnRollbackOnError",
+			fn: func(t *testing.T, name string) {
+				testTxnRollbackOnError(t, name)
+			}}, {
+			name: "TestTxnView",
+			fn: func(t *testing.T, name string) {
+				testTxnView(t, name)
+			}}, {
+			name: "TestTxnConflictRetry",
+			fn: func(t *testing.T, name string) {
+				testTxnConflictRetry(t, name)
+			}}, {
+			name: "TestTxnScanConflictRetry",
+			fn: func(t *testing.T, name string) {
+				testTxnScanConflictRetry(t, name)
+			}}, {
+			name: "TestTxnConcurrentIncrement",
+			fn: func(t *testing.T, name string) {
+				testTxnConcurrentIncrement(t, name)
+			}},
+	}
+	for i := range dbs {
+		for tt := range list_test {
+			testname := fmt.Sprintf("%s%s", list_test[tt].name, dbs[i])
+			t.Run(testname, func(t *testing.T) {
+				list_test[tt].fn(t, dbs[i])
+			})
+		}
+	}
+}
+
+func testTxnReadOwnWrites(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 3)
+	err := db.Update(t.Context(), func(tx zerokv.Txn) error {
+		require.NoError(t, tx.Put([]byte("key_10"), []byte("staged")))
+		require.NoError(t, tx.Delete(keys[0]))
+		value, err := tx.Get([]byte("key_10"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("staged"), value)
+		_, err = tx.Get(keys[0])
+		require.ErrorIs(t, err, zerokv.ErrNotFound)
+		got := collectKeys(t, tx.Scan([]byte("key_")))
+		require.Equal(t, [][]byte{keys[1], keys[2], []byte("key_10")}, got)
+		// nothing is visible outside the transaction before commit
+		_, err = db.Get(t.Context(), []byte("key_10"))
+		require.ErrorIs(t, err, zerokv.ErrNotFound)
+		return nil
+	})
+	require.NoError(t, err)
+	value, err := db.Get(t.Context(), []byte("key_10"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("staged"), value)
+	_, err = db.Get(t.Context(), keys[0])
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+}
+
+func testTxnRollbackOnError(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	errAbort := errors.New("abort")
+	err := db.Update(t.Context(), func(tx zerokv.Txn) error {
+		require.NoError(t, tx.Put([]byte("aborted"), []byte("value")))
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+	_, err = db.Get(t.Context(), []byte("aborted"))
+	require.ErrorIs(t, err, zerokv.ErrNotFound)
+}
+
+func testTxnView(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 5)
+	err := db.View(t.Context(), func(tx zerokv.Txn) error {
+		value, err := tx.Get(keys[2])
+		require.NoError(t, err)
+		require.Equal(t, []byte("value_02"), value)
+		require.Equal(t, keys, collectKeys(t, tx.Scan([]byte("key_"))))
+		require.ErrorIs(t, tx.Put(keys[0], []byte("nope")), zerokv.ErrReadOnly)
+		require.ErrorIs(t, tx.Delete(keys[0]), zerokv.ErrReadOnly)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// testTxnConflictRetry tests that a write to a key read by a transaction forces a retry.
+func testTxnConflictRetry(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	key := []byte("contended")
+	require.NoError(t, db.Put(t.Context(), key, []byte("initial")))
+	attempts := 0
+	err := db.Update(t.Context(), func(tx zerokv.Txn) error {
+		attempts++
+		value, err := tx.Get(key)
+		if err != nil {
+			return err
+		}
+		if attempts == 1 {
+			// a concurrent writer commits between our read and our commit
+			require.NoError(t, db.Put(t.Context(), key, []byte("concurrent")))
+		}
+		return tx.Put(key, append(value, []byte("+txn")...))
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+	value, err := db.Get(t.Context(), key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("concurrent+txn"), value)
+}
+
+// testTxnScanConflictRetry tests that a change to a scanned key forces a retry.
+func testTxnScanConflictRetry(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	keys := FillOrdered(t, db, 3)
+	attempts := 0
+	err := db.Update(t.Context(), func(tx zerokv.Txn) error {
+		attempts++
+		var joined []byte
+		it := tx.Scan([]byte("key_"))
+		for it.Next() {
+			joined = append(joined, it.Value()...)
+		}
+		it.Release()
+		if attempts == 1 {
+			require.NoError(t, db.Put(t.Context(), keys[1], []byte("changed")))
+		}
+		return tx.Put([]byte("joined"), joined)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+	value, err := db.Get(t.Context(), []byte("joined"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value_00changedvalue_02"), value)
+}
+
+// testTxnConcurrentIncrement tests read-modify-write from many goroutines.
+func testTxnConcurrentIncrement(t *testing.T, name string) {
+	db := helpers.SetupDB(t, name)
+	defer db.Close()
+	key := []byte("counter")
+	const workers, increments = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*increments)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range increments {
+				errs <- db.Update(t.Context(), func(tx zerokv.Txn) error {
+					var counter uint64
+					value, err := tx.Get(key)
+					switch {
+					case err == nil:
+						counter = binary.BigEndian.Uint64(value)
+					case !zerokv.IsNotFound(err):
+						return err
+					}
+					return tx.Put(key, binary.BigEndian.AppendUint64(nil, counter+1))
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	value, err := db.Get(t.Context(), key)
+	require.NoError(t, err)
+	require.Equal(t, uint64(workers*increments), binary.BigEndian.Uint64(value))
+}
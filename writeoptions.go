@@ -0,0 +1,28 @@
+package zerokv
+
+/*
+Writes are synced unless told otherwise: Put, Delete and Batch.Commit return once
+the write reached stable storage. Passing WriteOptions{Sync: false} trades that for
+throughput, a crash may then lose the latest writes but never applies one halfway.
+Writes that take no WriteOptions, PutWithTTL, Update and the atomic helpers, are
+always synced, on every backend.
+Core.Sync makes every write acknowledged so far durable, so a bulk load can skip
+syncing each write and sync once at the end.
+*/
+
+// WriteOptions control the durability of a single write.
+type WriteOptions struct {
+	// Sync waits for the write to reach stable storage before returning.
+	Sync bool
+}
+
+// DefaultWriteOptions apply to writes given no WriteOptions.
+var DefaultWriteOptions = WriteOptions{Sync: true}
+
+// WriteOptionsOf returns the last of opts, or DefaultWriteOptions when opts is empty.
+func WriteOptionsOf(opts []WriteOptions) WriteOptions {
+	if len(opts) == 0 {
+		return DefaultWriteOptions
+	}
+	return opts[len(opts)-1]
+}